@@ -0,0 +1,389 @@
+package basic
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// ToBigInt converts any type to *big.Int.
+func ToBigInt(value interface{}) *big.Int {
+	result, _ := ToBigIntE(value)
+	return result
+}
+
+// ToBigIntE converts any type to *big.Int with error.
+// Strings are parsed with arbitrary precision and may use a base prefix
+// ("0x", "0o", "0b") or scientific notation, as long as the value is an integer.
+// Floating-point inputs are truncated towards zero.
+func ToBigIntE(value interface{}) (*big.Int, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, nil
+		}
+		return new(big.Int).Set(v), nil
+	case *big.Float:
+		if v == nil {
+			return nil, nil
+		}
+		return bigTruncInt(v, "*big.Int")
+	case *big.Rat:
+		if v == nil {
+			return nil, nil
+		}
+		return bigTruncInt(v, "*big.Int")
+	case Decimal:
+		return bigTruncInt(v, "*big.Int")
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case int32:
+		return big.NewInt(int64(v)), nil
+	case int16:
+		return big.NewInt(int64(v)), nil
+	case int8:
+		return big.NewInt(int64(v)), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, internal.NewConversionError(value, "*big.Int", internal.ErrOverflow)
+		}
+		i, _ := big.NewFloat(v).Int(nil)
+		return i, nil
+	case float32:
+		return ToBigIntE(float64(v))
+	case bool:
+		if v {
+			return big.NewInt(1), nil
+		}
+		return big.NewInt(0), nil
+	case string:
+		return parseBigInt(value, v)
+	case json.Number:
+		return parseBigInt(value, string(v))
+	default:
+		if dv, ok, err := driverValue(value, "*big.Int"); ok {
+			if err != nil {
+				return nil, err
+			}
+			return ToBigIntE(dv)
+		}
+		return nil, internal.NewConversionError(value, "*big.Int", internal.ErrUnsupportedType)
+	}
+}
+
+// ToBigFloat converts any type to *big.Float.
+func ToBigFloat(value interface{}) *big.Float {
+	result, _ := ToBigFloatE(value)
+	return result
+}
+
+// ToBigFloatE converts any type to *big.Float with error.
+// Strings are parsed directly, with enough precision to hold every digit of the input.
+func ToBigFloatE(value interface{}) (*big.Float, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch v := value.(type) {
+	case *big.Float:
+		if v == nil {
+			return nil, nil
+		}
+		return new(big.Float).Copy(v), nil
+	case *big.Int:
+		if v == nil {
+			return nil, nil
+		}
+		return new(big.Float).SetInt(v), nil
+	case *big.Rat:
+		if v == nil {
+			return nil, nil
+		}
+		return new(big.Float).SetRat(v), nil
+	case Decimal:
+		return new(big.Float).SetRat(v.Rat()), nil
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8, bool:
+		i, err := ToBigIntE(value)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Float).SetInt(i), nil
+	case float64:
+		if math.IsNaN(v) {
+			return nil, internal.NewConversionError(value, "*big.Float", internal.ErrConversionFailed)
+		}
+		return big.NewFloat(v), nil
+	case float32:
+		return ToBigFloatE(float64(v))
+	case json.Number:
+		return ToBigFloatE(string(v))
+	case string:
+		s := strings.TrimSpace(v)
+		prec := uint(len(s)) * 4
+		if prec < 64 {
+			prec = 64
+		}
+		f, _, err := big.ParseFloat(s, 0, prec, big.ToNearestEven)
+		if err != nil {
+			return nil, internal.NewConversionError(value, "*big.Float", err)
+		}
+		return f, nil
+	default:
+		if dv, ok, err := driverValue(value, "*big.Float"); ok {
+			if err != nil {
+				return nil, err
+			}
+			return ToBigFloatE(dv)
+		}
+		return nil, internal.NewConversionError(value, "*big.Float", internal.ErrUnsupportedType)
+	}
+}
+
+// ToBigRat converts any type to *big.Rat.
+func ToBigRat(value interface{}) *big.Rat {
+	result, _ := ToBigRatE(value)
+	return result
+}
+
+// ToBigRatE converts any type to *big.Rat with error.
+// Strings may be fractions ("1/3") or decimal numbers with an optional exponent,
+// and are converted exactly.
+func ToBigRatE(value interface{}) (*big.Rat, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch v := value.(type) {
+	case *big.Rat:
+		if v == nil {
+			return nil, nil
+		}
+		return new(big.Rat).Set(v), nil
+	case *big.Int:
+		if v == nil {
+			return nil, nil
+		}
+		return new(big.Rat).SetInt(v), nil
+	case *big.Float:
+		if v == nil {
+			return nil, nil
+		}
+		if v.IsInf() {
+			return nil, internal.NewConversionError(value, "*big.Rat", internal.ErrOverflow)
+		}
+		r, _ := v.Rat(nil)
+		return r, nil
+	case Decimal:
+		return v.Rat(), nil
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8, bool:
+		i, err := ToBigIntE(value)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Rat).SetInt(i), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, internal.NewConversionError(value, "*big.Rat", internal.ErrConversionFailed)
+		}
+		return new(big.Rat).SetFloat64(v), nil
+	case float32:
+		return ToBigRatE(float64(v))
+	case json.Number:
+		return ToBigRatE(string(v))
+	case string:
+		r, ok := new(big.Rat).SetString(strings.TrimSpace(v))
+		if !ok {
+			return nil, internal.NewConversionError(value, "*big.Rat", internal.ErrInvalidFormat)
+		}
+		return r, nil
+	default:
+		if dv, ok, err := driverValue(value, "*big.Rat"); ok {
+			if err != nil {
+				return nil, err
+			}
+			return ToBigRatE(dv)
+		}
+		return nil, internal.NewConversionError(value, "*big.Rat", internal.ErrUnsupportedType)
+	}
+}
+
+// parseBigInt parses an integer string of arbitrary size.
+func parseBigInt(value interface{}, s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if i, ok := new(big.Int).SetString(s, 0); ok {
+		return i, nil
+	}
+	// Fall back to an exact rational parse to accept forms such as "1.5e3".
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, internal.NewConversionError(value, "*big.Int", internal.ErrInvalidFormat)
+	}
+	if !r.IsInt() {
+		return nil, internal.NewConversionError(value, "*big.Int", internal.ErrConversionFailed)
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// bigTruncInt returns the integer part of a *big.Int, *big.Float, *big.Rat or Decimal value.
+// A nil pointer is treated as zero.
+func bigTruncInt(value interface{}, targetType string) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return new(big.Int), nil
+		}
+		return v, nil
+	case *big.Float:
+		if v == nil {
+			return new(big.Int), nil
+		}
+		if v.IsInf() {
+			return nil, internal.NewConversionError(value, targetType, internal.ErrOverflow)
+		}
+		i, _ := v.Int(nil)
+		return i, nil
+	case *big.Rat:
+		if v == nil {
+			return new(big.Int), nil
+		}
+		return new(big.Int).Quo(v.Num(), v.Denom()), nil
+	case Decimal:
+		return new(big.Int).Quo(v.coefficient(), pow10(int64(v.scale))), nil
+	default:
+		return nil, internal.NewConversionError(value, targetType, internal.ErrUnsupportedType)
+	}
+}
+
+// bigToInt64 converts a big number to a signed integer that fits in bitSize bits.
+func bigToInt64(value interface{}, targetType string, bitSize int) (int64, error) {
+	i, err := bigTruncInt(value, targetType)
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, internal.NewConversionError(value, targetType, internal.ErrOverflow)
+	}
+	n := i.Int64()
+	if bitSize < 64 && (n > 1<<(bitSize-1)-1 || n < -1<<(bitSize-1)) {
+		return 0, internal.NewConversionError(value, targetType, internal.ErrOverflow)
+	}
+	return n, nil
+}
+
+// bigToUint64 converts a big number to an unsigned integer that fits in bitSize bits.
+func bigToUint64(value interface{}, targetType string, bitSize int) (uint64, error) {
+	i, err := bigTruncInt(value, targetType)
+	if err != nil {
+		return 0, err
+	}
+	if i.Sign() < 0 || i.BitLen() > bitSize {
+		return 0, internal.NewConversionError(value, targetType, internal.ErrOverflow)
+	}
+	return i.Uint64(), nil
+}
+
+// bigToFloat64 converts a big number to the nearest float64.
+func bigToFloat64(value interface{}, targetType string) (float64, error) {
+	var f float64
+	switch v := value.(type) {
+	case *big.Int:
+		if v != nil {
+			f, _ = new(big.Float).SetInt(v).Float64()
+		}
+	case *big.Float:
+		if v != nil {
+			f, _ = v.Float64()
+		}
+	case *big.Rat:
+		if v != nil {
+			f, _ = v.Float64()
+		}
+	case Decimal:
+		f, _ = v.Float64()
+	default:
+		return 0, internal.NewConversionError(value, targetType, internal.ErrUnsupportedType)
+	}
+	if math.IsInf(f, 0) && !isBigInf(value) {
+		return 0, internal.NewConversionError(value, targetType, internal.ErrOverflow)
+	}
+	return f, nil
+}
+
+// bigToFloat32 converts a big number to the nearest float32.
+func bigToFloat32(value interface{}, targetType string) (float32, error) {
+	f, err := bigToFloat64(value, targetType)
+	if err != nil {
+		return 0, err
+	}
+	if !math.IsInf(f, 0) && (f > math.MaxFloat32 || f < -math.MaxFloat32) {
+		return 0, internal.NewConversionError(value, targetType, internal.ErrOverflow)
+	}
+	return float32(f), nil
+}
+
+// bigSign returns the sign of a big number, treating nil pointers as zero.
+func bigSign(value interface{}) int {
+	switch v := value.(type) {
+	case *big.Int:
+		if v != nil {
+			return v.Sign()
+		}
+	case *big.Float:
+		if v != nil {
+			return v.Sign()
+		}
+	case *big.Rat:
+		if v != nil {
+			return v.Sign()
+		}
+	case Decimal:
+		return v.Sign()
+	}
+	return 0
+}
+
+// isBigInf reports whether value is an infinite *big.Float.
+func isBigInf(value interface{}) bool {
+	f, ok := value.(*big.Float)
+	return ok && f != nil && f.IsInf()
+}
+
+// formatBig formats a big number or Decimal as an exact decimal string.
+func formatBig(value interface{}) string {
+	switch v := value.(type) {
+	case *big.Int:
+		if v != nil {
+			return v.String()
+		}
+	case *big.Float:
+		if v != nil {
+			return v.Text('f', -1)
+		}
+	case *big.Rat:
+		if v != nil {
+			return v.RatString()
+		}
+	case Decimal:
+		return v.String()
+	}
+	return ""
+}
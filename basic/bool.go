@@ -1,12 +1,102 @@
 package basic
 
 import (
+	"encoding/json"
+	"math/big"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/graingo/mconv/internal"
 )
 
+// defaultBoolWords is the built-in vocabulary used by ToBoolE for strings.
+var defaultBoolWords = map[string]bool{
+	"true":     true,
+	"yes":      true,
+	"y":        true,
+	"t":        true,
+	"1":        true,
+	"on":       true,
+	"enable":   true,
+	"enabled":  true,
+	"false":    false,
+	"no":       false,
+	"n":        false,
+	"f":        false,
+	"0":        false,
+	"off":      false,
+	"disable":  false,
+	"disabled": false,
+}
+
+// boolWords holds the active vocabulary as an immutable map[string]bool keyed by
+// lower-case word. Writers replace the whole map under boolWordsMu, so readers never
+// observe a partially applied change.
+var (
+	boolWords   atomic.Value
+	boolWordsMu sync.Mutex
+)
+
+func init() {
+	ResetBoolWords()
+}
+
+// RegisterBoolWords adds words that ToBoolE maps to the given value.
+// Words are matched case-insensitively after trimming surrounding whitespace,
+// which makes it possible to plug in localized vocabularies, for example:
+//
+//	RegisterBoolWords(true, "是", "ja", "oui")
+//	RegisterBoolWords(false, "否", "nein", "non")
+func RegisterBoolWords(value bool, words ...string) {
+	updateBoolWords(func(vocabulary map[string]bool) {
+		for _, w := range words {
+			if w = normalizeBoolWord(w); w != "" {
+				vocabulary[w] = value
+			}
+		}
+	})
+}
+
+// UnregisterBoolWords removes words from the vocabulary used by ToBoolE.
+func UnregisterBoolWords(words ...string) {
+	updateBoolWords(func(vocabulary map[string]bool) {
+		for _, w := range words {
+			delete(vocabulary, normalizeBoolWord(w))
+		}
+	})
+}
+
+// ResetBoolWords restores the built-in vocabulary used by ToBoolE.
+func ResetBoolWords() {
+	vocabulary := make(map[string]bool, len(defaultBoolWords))
+	for w, b := range defaultBoolWords {
+		vocabulary[w] = b
+	}
+	boolWordsMu.Lock()
+	defer boolWordsMu.Unlock()
+	boolWords.Store(vocabulary)
+}
+
+// updateBoolWords applies update to a copy of the vocabulary and publishes the copy.
+func updateBoolWords(update func(vocabulary map[string]bool)) {
+	boolWordsMu.Lock()
+	defer boolWordsMu.Unlock()
+	current, _ := boolWords.Load().(map[string]bool)
+	vocabulary := make(map[string]bool, len(current))
+	for w, b := range current {
+		vocabulary[w] = b
+	}
+	update(vocabulary)
+	boolWords.Store(vocabulary)
+}
+
+// normalizeBoolWord trims and lower-cases a word for vocabulary lookups.
+func normalizeBoolWord(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
 // ToBool converts any type to bool.
 func ToBool(value interface{}) bool {
 	result, _ := ToBoolE(value)
@@ -14,6 +104,7 @@ func ToBool(value interface{}) bool {
 }
 
 // ToBoolE converts any type to bool with error.
+// Strings are looked up in the registered vocabulary, see RegisterBoolWords.
 func ToBoolE(value interface{}) (bool, error) {
 	if value == nil {
 		return false, nil
@@ -50,12 +141,18 @@ func ToBoolE(value interface{}) (bool, error) {
 		return real(v) != 0 || imag(v) != 0, nil
 	case complex128:
 		return real(v) != 0 || imag(v) != 0, nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		return bigSign(value) != 0, nil
+	case json.Number:
+		r, ok := new(big.Rat).SetString(string(v))
+		if !ok {
+			return false, internal.NewConversionError(value, "bool", internal.ErrInvalidFormat)
+		}
+		return r.Sign() != 0, nil
 	case string:
-		s := strings.ToLower(v)
-		if s == "true" || s == "yes" || s == "y" || s == "1" {
-			return true, nil
-		} else if s == "false" || s == "no" || s == "n" || s == "0" {
-			return false, nil
+		s := normalizeBoolWord(v)
+		if b, ok := boolWords.Load().(map[string]bool)[s]; ok {
+			return b, nil
 		}
 		b, err := strconv.ParseBool(s)
 		if err != nil {
@@ -63,6 +160,45 @@ func ToBoolE(value interface{}) (bool, error) {
 		}
 		return b, nil
 	default:
+		if dv, ok, err := driverValue(value, "bool"); ok {
+			if err != nil {
+				return false, err
+			}
+			return ToBoolE(dv)
+		}
+		return false, internal.NewConversionError(value, "bool", internal.ErrUnsupportedType)
+	}
+}
+
+// ToBoolStrict converts a bool or a "true"/"false" string to bool.
+func ToBoolStrict(value interface{}) bool {
+	result, _ := ToBoolStrictE(value)
+	return result
+}
+
+// ToBoolStrictE converts a bool or a "true"/"false" string to bool with error.
+// Unlike ToBoolE it ignores the registered vocabulary and rejects numbers,
+// so inputs such as "yes" or 1 result in an error.
+func ToBoolStrictE(value interface{}) (bool, error) {
+	if value == nil {
+		return false, nil
+	}
+
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch normalizeBoolWord(v) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return false, internal.NewConversionError(value, "bool", internal.ErrInvalidFormat)
+	default:
+		if v, ok := internal.Unwrap(value); ok {
+			return ToBoolStrictE(v)
+		}
 		return false, internal.NewConversionError(value, "bool", internal.ErrUnsupportedType)
 	}
 }
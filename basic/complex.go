@@ -1,6 +1,8 @@
 package basic
 
 import (
+	"encoding/json"
+	"math/big"
 	"strconv"
 
 	"github.com/graingo/mconv/internal"
@@ -41,6 +43,18 @@ func ToComplex128E(value interface{}) (complex128, error) {
 		return complex(v, 0), nil
 	case float32:
 		return complex(float64(v), 0), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		f, err := bigToFloat64(value, "complex128")
+		if err != nil {
+			return 0, err
+		}
+		return complex(f, 0), nil
+	case json.Number:
+		f, err := jsonNumberToFloat64(v, "complex128", 64)
+		if err != nil {
+			return 0, err
+		}
+		return complex(f, 0), nil
 	case bool:
 		if v {
 			return 1, nil
@@ -59,6 +73,12 @@ func ToComplex128E(value interface{}) (complex128, error) {
 		}
 		return c, nil
 	default:
+		if dv, ok, err := driverValue(value, "complex128"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToComplex128E(dv)
+		}
 		return 0, internal.NewConversionError(value, "complex128", internal.ErrUnsupportedType)
 	}
 }
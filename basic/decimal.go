@@ -0,0 +1,466 @@
+package basic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// maxDecimalExponent bounds the exponent accepted by ParseDecimal,
+// so that inputs like "1e999999999" cannot allocate unbounded memory.
+const maxDecimalExponent = 1 << 16
+
+// RoundingMode determines how a Decimal is rounded when its scale is reduced.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest neighbour, ties away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest neighbour, ties to the even neighbour.
+	RoundHalfEven
+	// RoundHalfDown rounds to the nearest neighbour, ties towards zero.
+	RoundHalfDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundDown rounds towards zero (truncation).
+	RoundDown
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+)
+
+// Decimal is an arbitrary-precision decimal number with a fixed scale.
+// It represents coef / 10^scale exactly, which makes it suitable for monetary values.
+// The zero value is 0 with scale 0. Decimal values are immutable.
+type Decimal struct {
+	coef  *big.Int // unscaled value, nil means zero
+	scale int32    // number of digits after the decimal point, never negative
+}
+
+var bigTen = big.NewInt(10)
+
+// NewDecimal returns the Decimal unscaled * 10^-scale.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return newDecimal(big.NewInt(unscaled), scale)
+}
+
+// NewDecimalFromBigInt returns the Decimal unscaled * 10^-scale.
+func NewDecimalFromBigInt(unscaled *big.Int, scale int32) Decimal {
+	if unscaled == nil {
+		return Decimal{}
+	}
+	return newDecimal(new(big.Int).Set(unscaled), scale)
+}
+
+// newDecimal takes ownership of coef and normalizes a negative scale.
+func newDecimal(coef *big.Int, scale int32) Decimal {
+	if scale < 0 {
+		coef.Mul(coef, pow10(int64(-scale)))
+		scale = 0
+	}
+	return Decimal{coef: coef, scale: scale}
+}
+
+// ParseDecimal parses a decimal string such as "19.99", "-0.5" or "1.25e3".
+// The scale of the result is the number of digits after the decimal point,
+// adjusted by the exponent; trailing zeros are kept, so "1.50" has scale 2.
+func ParseDecimal(s string) (Decimal, error) {
+	d, err := parseDecimal(s)
+	if err != nil {
+		return Decimal{}, internal.NewConversionError(s, "Decimal", err)
+	}
+	return d, nil
+}
+
+func parseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	mantissa, exp := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 64)
+		if err != nil || e > maxDecimalExponent || e < -maxDecimalExponent {
+			return Decimal{}, internal.ErrInvalidFormat
+		}
+		mantissa, exp = s[:i], e
+	}
+
+	neg := false
+	if mantissa != "" && (mantissa[0] == '+' || mantissa[0] == '-') {
+		neg = mantissa[0] == '-'
+		mantissa = mantissa[1:]
+	}
+
+	intPart, fracPart := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, fracPart = mantissa[:i], mantissa[i+1:]
+	}
+	digits := intPart + fracPart
+	if digits == "" {
+		return Decimal{}, internal.ErrInvalidFormat
+	}
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return Decimal{}, internal.ErrInvalidFormat
+		}
+	}
+
+	coef, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		coef.Neg(coef)
+	}
+	scale := int64(len(fracPart)) - exp
+	if scale > math.MaxInt32 {
+		return Decimal{}, internal.ErrInvalidFormat
+	}
+	return newDecimal(coef, int32(scale)), nil
+}
+
+// ToDecimal converts any type to Decimal.
+func ToDecimal(value interface{}) Decimal {
+	result, _ := ToDecimalE(value)
+	return result
+}
+
+// ToDecimalE converts any type to Decimal with error.
+// Floating-point values are converted through their shortest decimal representation,
+// so 19.99 becomes exactly 19.99. A *big.Rat must have a terminating decimal expansion.
+func ToDecimalE(value interface{}) (Decimal, error) {
+	if value == nil {
+		return Decimal{}, nil
+	}
+
+	switch v := value.(type) {
+	case Decimal:
+		return v, nil
+	case *Decimal:
+		if v == nil {
+			return Decimal{}, nil
+		}
+		return *v, nil
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8, bool:
+		i, err := ToBigIntE(value)
+		if err != nil {
+			return Decimal{}, err
+		}
+		return Decimal{coef: i}, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return Decimal{}, internal.NewConversionError(value, "Decimal", internal.ErrConversionFailed)
+		}
+		return parseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	case float32:
+		f := float64(v)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return Decimal{}, internal.NewConversionError(value, "Decimal", internal.ErrConversionFailed)
+		}
+		return parseDecimal(strconv.FormatFloat(f, 'f', -1, 32))
+	case string:
+		d, err := parseDecimal(v)
+		if err != nil {
+			return Decimal{}, internal.NewConversionError(value, "Decimal", err)
+		}
+		return d, nil
+	case json.Number:
+		d, err := parseDecimal(string(v))
+		if err != nil {
+			return Decimal{}, internal.NewConversionError(value, "Decimal", err)
+		}
+		return d, nil
+	case *big.Int:
+		if v == nil {
+			return Decimal{}, nil
+		}
+		return Decimal{coef: new(big.Int).Set(v)}, nil
+	case *big.Float:
+		if v == nil {
+			return Decimal{}, nil
+		}
+		if v.IsInf() {
+			return Decimal{}, internal.NewConversionError(value, "Decimal", internal.ErrOverflow)
+		}
+		return parseDecimal(v.Text('f', -1))
+	case *big.Rat:
+		if v == nil {
+			return Decimal{}, nil
+		}
+		d, ok := decimalFromRat(v)
+		if !ok {
+			return Decimal{}, internal.NewConversionError(value, "Decimal", internal.ErrConversionFailed)
+		}
+		return d, nil
+	default:
+		if dv, ok, err := driverValue(value, "Decimal"); ok {
+			if err != nil {
+				return Decimal{}, err
+			}
+			return ToDecimalE(dv)
+		}
+		return Decimal{}, internal.NewConversionError(value, "Decimal", internal.ErrUnsupportedType)
+	}
+}
+
+// decimalFromRat converts r exactly, reporting false if r has no terminating decimal expansion.
+func decimalFromRat(r *big.Rat) (Decimal, bool) {
+	if r.IsInt() {
+		return Decimal{coef: new(big.Int).Set(r.Num())}, true
+	}
+
+	// The expansion terminates iff the denominator is of the form 2^a * 5^b,
+	// in which case max(a, b) digits are needed after the decimal point.
+	denom := new(big.Int).Set(r.Denom())
+	var twos, fives int32
+	two, five, rem := big.NewInt(2), big.NewInt(5), new(big.Int)
+	for q := new(big.Int); ; twos++ {
+		if q.QuoRem(denom, two, rem); rem.Sign() != 0 {
+			break
+		}
+		denom.Set(q)
+	}
+	for q := new(big.Int); ; fives++ {
+		if q.QuoRem(denom, five, rem); rem.Sign() != 0 {
+			break
+		}
+		denom.Set(q)
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return Decimal{}, false
+	}
+
+	scale := twos
+	if fives > scale {
+		scale = fives
+	}
+	coef := new(big.Int).Mul(r.Num(), pow10(int64(scale)))
+	coef.Quo(coef, r.Denom())
+	return Decimal{coef: coef, scale: scale}, true
+}
+
+// pow10 returns 10^n.
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(n), nil)
+}
+
+// coefficient returns the unscaled value, never nil.
+func (d Decimal) coefficient() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// Coefficient returns a copy of the unscaled value of d.
+func (d Decimal) Coefficient() *big.Int {
+	return new(big.Int).Set(d.coefficient())
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.coefficient().Sign()
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// rescale returns the coefficient of d expressed with the given larger or equal scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.coefficient()
+	}
+	return new(big.Int).Mul(d.coefficient(), pow10(int64(scale-d.scale)))
+}
+
+// align returns the coefficients of d and d2 at their common scale.
+func (d Decimal) align(d2 Decimal) (*big.Int, *big.Int, int32) {
+	scale := d.scale
+	if d2.scale > scale {
+		scale = d2.scale
+	}
+	return d.rescale(scale), d2.rescale(scale), scale
+}
+
+// Cmp compares d and d2 and returns -1, 0 or +1.
+func (d Decimal) Cmp(d2 Decimal) int {
+	a, b, _ := d.align(d2)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and d2 represent the same number, regardless of scale.
+func (d Decimal) Equal(d2 Decimal) bool {
+	return d.Cmp(d2) == 0
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.coefficient()), scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.coefficient()), scale: d.scale}
+}
+
+// Add returns d + d2 with the larger of the two scales.
+func (d Decimal) Add(d2 Decimal) Decimal {
+	a, b, scale := d.align(d2)
+	return Decimal{coef: new(big.Int).Add(a, b), scale: scale}
+}
+
+// Sub returns d - d2 with the larger of the two scales.
+func (d Decimal) Sub(d2 Decimal) Decimal {
+	a, b, scale := d.align(d2)
+	return Decimal{coef: new(big.Int).Sub(a, b), scale: scale}
+}
+
+// Mul returns d * d2 with the sum of the two scales.
+func (d Decimal) Mul(d2 Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.coefficient(), d2.coefficient()), scale: d.scale + d2.scale}
+}
+
+// DivRound returns d / d2 rounded to the given scale using mode.
+func (d Decimal) DivRound(d2 Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if d2.IsZero() {
+		return Decimal{}, errors.New("decimal division by zero")
+	}
+	if scale < 0 {
+		scale = 0
+	}
+	// d / d2 = (d.coef * 10^(scale + d2.scale - d.scale)) / d2.coef, scaled by 10^-scale.
+	num := new(big.Int).Set(d.coefficient())
+	denom := new(big.Int).Set(d2.coefficient())
+	if shift := int64(scale) + int64(d2.scale) - int64(d.scale); shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		denom.Mul(denom, pow10(-shift))
+	}
+	return Decimal{coef: roundQuo(num, denom, mode), scale: scale}, nil
+}
+
+// Round returns d rounded to the given number of digits after the decimal point.
+// If places is larger than the current scale, d is padded with zeros.
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return Decimal{coef: d.rescale(places), scale: places}
+	}
+	return Decimal{coef: roundQuo(d.coefficient(), pow10(int64(d.scale-places)), mode), scale: places}
+}
+
+// roundQuo returns num / denom rounded to an integer using mode.
+func roundQuo(num, denom *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	// sign of the exact quotient
+	sign := num.Sign() * denom.Sign()
+	var away bool
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundCeiling:
+		away = sign > 0
+	case RoundFloor:
+		away = sign < 0
+	default:
+		half := new(big.Int).Abs(r)
+		half.Lsh(half, 1)
+		switch c := half.CmpAbs(denom); {
+		case c > 0:
+			away = true
+		case c < 0:
+			away = false
+		case mode == RoundHalfUp:
+			away = true
+		case mode == RoundHalfEven:
+			away = q.Bit(0) == 1
+		default:
+			away = false
+		}
+	}
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+// Rat returns d as an exact *big.Rat.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.coefficient(), pow10(int64(d.scale)))
+}
+
+// Float64 returns the nearest float64 value for d and whether it is exact.
+func (d Decimal) Float64() (float64, bool) {
+	return d.Rat().Float64()
+}
+
+// String returns d in plain decimal notation, keeping all digits of its scale.
+func (d Decimal) String() string {
+	coef := d.coefficient()
+	digits := new(big.Int).Abs(coef).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if coef.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The value is encoded as a JSON string
+// so that no precision is lost by consumers that decode numbers as float64.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both JSON strings and numbers are accepted.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := parseDecimal(s)
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal %s into Decimal: %w", data, err)
+	}
+	*d = parsed
+	return nil
+}
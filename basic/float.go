@@ -1,6 +1,8 @@
 package basic
 
 import (
+	"encoding/json"
+	"math/big"
 	"strconv"
 
 	"github.com/graingo/mconv/internal"
@@ -53,12 +55,26 @@ func ToFloat64E(value interface{}) (float64, error) {
 			return 0, internal.NewConversionError(value, "float64", err)
 		}
 		return f, nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		return bigToFloat64(value, "float64")
+	case json.Number:
+		i, err := jsonNumberToFloat64(v, "float64", 64)
+		if err != nil {
+			return 0, err
+		}
+		return i, nil
 	case bool:
 		if v {
 			return 1, nil
 		}
 		return 0, nil
 	default:
+		if dv, ok, err := driverValue(value, "float64"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToFloat64E(dv)
+		}
 		return 0, internal.NewConversionError(value, "float64", internal.ErrUnsupportedType)
 	}
 }
@@ -110,12 +126,26 @@ func ToFloat32E(value interface{}) (float32, error) {
 			return 0, internal.NewConversionError(value, "float32", err)
 		}
 		return float32(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		return bigToFloat32(value, "float32")
+	case json.Number:
+		i, err := jsonNumberToFloat64(v, "float32", 32)
+		if err != nil {
+			return 0, err
+		}
+		return float32(i), nil
 	case bool:
 		if v {
 			return 1, nil
 		}
 		return 0, nil
 	default:
+		if dv, ok, err := driverValue(value, "float32"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToFloat32E(dv)
+		}
 		return 0, internal.NewConversionError(value, "float32", internal.ErrUnsupportedType)
 	}
 }
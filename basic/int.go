@@ -1,6 +1,8 @@
 package basic
 
 import (
+	"encoding/json"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -80,6 +82,18 @@ func ToIntE(value interface{}) (int, error) {
 			return 0, internal.NewConversionError(value, "int", internal.ErrOverflow)
 		}
 		return int(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		i, err := bigToInt64(value, "int", strconv.IntSize)
+		if err != nil {
+			return 0, err
+		}
+		return int(i), nil
+	case json.Number:
+		i, err := jsonNumberToInt64(v, "int", strconv.IntSize)
+		if err != nil {
+			return 0, err
+		}
+		return int(i), nil
 	case bool:
 		if v {
 			return 1, nil
@@ -92,6 +106,12 @@ func ToIntE(value interface{}) (int, error) {
 		}
 		return int(i), nil
 	default:
+		if dv, ok, err := driverValue(value, "int"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToIntE(dv)
+		}
 		return 0, internal.NewConversionError(value, "int", internal.ErrUnsupportedType)
 	}
 }
@@ -160,6 +180,18 @@ func ToInt64E(value interface{}) (int64, error) {
 			return 0, internal.NewConversionError(value, "int64", internal.ErrOverflow)
 		}
 		return int64(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		i, err := bigToInt64(value, "int64", 64)
+		if err != nil {
+			return 0, err
+		}
+		return i, nil
+	case json.Number:
+		i, err := jsonNumberToInt64(v, "int64", 64)
+		if err != nil {
+			return 0, err
+		}
+		return i, nil
 	case bool:
 		if v {
 			return 1, nil
@@ -172,6 +204,12 @@ func ToInt64E(value interface{}) (int64, error) {
 		}
 		return i, nil
 	default:
+		if dv, ok, err := driverValue(value, "int64"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToInt64E(dv)
+		}
 		return 0, internal.NewConversionError(value, "int64", internal.ErrUnsupportedType)
 	}
 }
@@ -252,6 +290,18 @@ func ToInt32E(value interface{}) (int32, error) {
 			return 0, internal.NewConversionError(value, "int32", internal.ErrOverflow)
 		}
 		return int32(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		i, err := bigToInt64(value, "int32", 32)
+		if err != nil {
+			return 0, err
+		}
+		return int32(i), nil
+	case json.Number:
+		i, err := jsonNumberToInt64(v, "int32", 32)
+		if err != nil {
+			return 0, err
+		}
+		return int32(i), nil
 	case bool:
 		if v {
 			return 1, nil
@@ -265,6 +315,12 @@ func ToInt32E(value interface{}) (int32, error) {
 		}
 		return int32(i), nil
 	default:
+		if dv, ok, err := driverValue(value, "int32"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToInt32E(dv)
+		}
 		return 0, internal.NewConversionError(value, "int32", internal.ErrUnsupportedType)
 	}
 }
@@ -351,6 +407,18 @@ func ToInt16E(value interface{}) (int16, error) {
 			return 0, internal.NewConversionError(value, "int16", internal.ErrOverflow)
 		}
 		return int16(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		i, err := bigToInt64(value, "int16", 16)
+		if err != nil {
+			return 0, err
+		}
+		return int16(i), nil
+	case json.Number:
+		i, err := jsonNumberToInt64(v, "int16", 16)
+		if err != nil {
+			return 0, err
+		}
+		return int16(i), nil
 	case bool:
 		if v {
 			return 1, nil
@@ -364,6 +432,12 @@ func ToInt16E(value interface{}) (int16, error) {
 		}
 		return int16(i), nil
 	default:
+		if dv, ok, err := driverValue(value, "int16"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToInt16E(dv)
+		}
 		return 0, internal.NewConversionError(value, "int16", internal.ErrUnsupportedType)
 	}
 }
@@ -456,6 +530,18 @@ func ToInt8E(value interface{}) (int8, error) {
 			return 0, internal.NewConversionError(value, "int8", internal.ErrOverflow)
 		}
 		return int8(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		i, err := bigToInt64(value, "int8", 8)
+		if err != nil {
+			return 0, err
+		}
+		return int8(i), nil
+	case json.Number:
+		i, err := jsonNumberToInt64(v, "int8", 8)
+		if err != nil {
+			return 0, err
+		}
+		return int8(i), nil
 	case bool:
 		if v {
 			return 1, nil
@@ -469,6 +555,12 @@ func ToInt8E(value interface{}) (int8, error) {
 		}
 		return int8(i), nil
 	default:
+		if dv, ok, err := driverValue(value, "int8"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToInt8E(dv)
+		}
 		return 0, internal.NewConversionError(value, "int8", internal.ErrUnsupportedType)
 	}
 }
@@ -0,0 +1,53 @@
+package basic
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// jsonNumberToInt64 parses n as a signed integer that fits in bitSize bits.
+// Integers are parsed exactly; numbers with a fraction or exponent are parsed
+// as exact rationals and truncated towards zero, so no precision is lost through float64.
+func jsonNumberToInt64(n json.Number, targetType string, bitSize int) (int64, error) {
+	if i, err := strconv.ParseInt(string(n), 10, bitSize); err == nil {
+		return i, nil
+	}
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return 0, internal.NewConversionError(n, targetType, internal.ErrInvalidFormat)
+	}
+	i, err := bigToInt64(r, targetType, bitSize)
+	if err != nil {
+		return 0, internal.NewConversionError(n, targetType, internal.ErrOverflow)
+	}
+	return i, nil
+}
+
+// jsonNumberToUint64 parses n as an unsigned integer that fits in bitSize bits.
+// See jsonNumberToInt64 for how fractions and exponents are handled.
+func jsonNumberToUint64(n json.Number, targetType string, bitSize int) (uint64, error) {
+	if u, err := strconv.ParseUint(string(n), 10, bitSize); err == nil {
+		return u, nil
+	}
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return 0, internal.NewConversionError(n, targetType, internal.ErrInvalidFormat)
+	}
+	u, err := bigToUint64(r, targetType, bitSize)
+	if err != nil {
+		return 0, internal.NewConversionError(n, targetType, internal.ErrOverflow)
+	}
+	return u, nil
+}
+
+// jsonNumberToFloat64 parses n as a float64 with the given bit size.
+func jsonNumberToFloat64(n json.Number, targetType string, bitSize int) (float64, error) {
+	f, err := strconv.ParseFloat(string(n), bitSize)
+	if err != nil {
+		return 0, internal.NewConversionError(n, targetType, err)
+	}
+	return f, nil
+}
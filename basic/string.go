@@ -1,9 +1,11 @@
 package basic
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"math/big"
 	"strconv"
 	"time"
 
@@ -68,8 +70,20 @@ func ToStringE(value interface{}) (string, error) {
 		result = string(v)
 	case json.Number:
 		result = string(v)
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		result = formatBig(v)
 	case time.Time:
 		result = v.Format(time.RFC3339)
+	case internal.Wrapper, driver.Valuer:
+		dv, ok, err := driverValue(v, "string")
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return ToStringE(dv)
+		}
+		// Only reflect.Value is left here: it has an Interface method but is not a wrapper.
+		result = value.(fmt.Stringer).String()
 	case fmt.Stringer:
 		result = v.String()
 	default:
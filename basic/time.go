@@ -1,6 +1,7 @@
 package basic
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -40,7 +41,17 @@ func ToTimeE(value interface{}, formats ...string) (time.Time, error) {
 		result = time.Unix(int64(v), 0)
 	case uint32:
 		result = time.Unix(int64(v), 0)
+	case json.Number:
+		var i int64
+		i, err = jsonNumberToInt64(v, "time.Time", 64)
+		result = time.Unix(i, 0)
 	default:
+		if dv, ok, err := driverValue(value, "time.Time"); ok {
+			if err != nil {
+				return time.Time{}, err
+			}
+			return ToTimeE(dv, formats...)
+		}
 		return time.Time{}, internal.NewConversionError(value, "time.Time", internal.ErrUnsupportedType)
 	}
 
@@ -134,6 +145,12 @@ func ToDurationE(value interface{}) (time.Duration, error) {
 		return time.Duration(v), nil // Float values are treated as nanoseconds
 	case float32:
 		return time.Duration(v), nil
+	case json.Number:
+		i, err := jsonNumberToInt64(v, "time.Duration", 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(i), nil
 	case string:
 		// Try to parse using time.ParseDuration first (e.g., "1h", "10m", "30s")
 		d, err := time.ParseDuration(v)
@@ -153,6 +170,12 @@ func ToDurationE(value interface{}) (time.Duration, error) {
 
 		return 0, internal.NewConversionError(value, "time.Duration", fmt.Errorf("cannot parse %q as duration", v))
 	default:
+		if dv, ok, err := driverValue(value, "time.Duration"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToDurationE(dv)
+		}
 		// Handle reflection cases
 		rv := reflect.ValueOf(value)
 		switch rv.Kind() {
@@ -1,7 +1,9 @@
 package basic
 
 import (
+	"encoding/json"
 	"math"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -88,6 +90,18 @@ func ToUintE(value interface{}) (uint, error) {
 			return 0, internal.NewConversionError(value, "uint", internal.ErrOverflow)
 		}
 		return uint(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		i, err := bigToUint64(value, "uint", strconv.IntSize)
+		if err != nil {
+			return 0, err
+		}
+		return uint(i), nil
+	case json.Number:
+		i, err := jsonNumberToUint64(v, "uint", strconv.IntSize)
+		if err != nil {
+			return 0, err
+		}
+		return uint(i), nil
 	case bool:
 		if v {
 			return 1, nil
@@ -101,6 +115,12 @@ func ToUintE(value interface{}) (uint, error) {
 		}
 		return uint(u), nil
 	default:
+		if dv, ok, err := driverValue(value, "uint"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToUintE(dv)
+		}
 		return 0, internal.NewConversionError(value, "uint", internal.ErrUnsupportedType)
 	}
 }
@@ -182,6 +202,18 @@ func ToUint64E(value interface{}) (uint64, error) {
 			return 0, internal.NewConversionError(value, "uint64", internal.ErrOverflow)
 		}
 		return uint64(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		i, err := bigToUint64(value, "uint64", 64)
+		if err != nil {
+			return 0, err
+		}
+		return i, nil
+	case json.Number:
+		i, err := jsonNumberToUint64(v, "uint64", 64)
+		if err != nil {
+			return 0, err
+		}
+		return i, nil
 	case bool:
 		if v {
 			return 1, nil
@@ -195,6 +227,12 @@ func ToUint64E(value interface{}) (uint64, error) {
 		}
 		return u, nil
 	default:
+		if dv, ok, err := driverValue(value, "uint64"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToUint64E(dv)
+		}
 		return 0, internal.NewConversionError(value, "uint64", internal.ErrUnsupportedType)
 	}
 }
@@ -282,6 +320,18 @@ func ToUint32E(value interface{}) (uint32, error) {
 			return 0, internal.NewConversionError(value, "uint32", internal.ErrOverflow)
 		}
 		return uint32(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		i, err := bigToUint64(value, "uint32", 32)
+		if err != nil {
+			return 0, err
+		}
+		return uint32(i), nil
+	case json.Number:
+		i, err := jsonNumberToUint64(v, "uint32", 32)
+		if err != nil {
+			return 0, err
+		}
+		return uint32(i), nil
 	case bool:
 		if v {
 			return 1, nil
@@ -295,6 +345,12 @@ func ToUint32E(value interface{}) (uint32, error) {
 		}
 		return uint32(u), nil
 	default:
+		if dv, ok, err := driverValue(value, "uint32"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToUint32E(dv)
+		}
 		return 0, internal.NewConversionError(value, "uint32", internal.ErrUnsupportedType)
 	}
 }
@@ -385,6 +441,18 @@ func ToUint16E(value interface{}) (uint16, error) {
 			return 0, internal.NewConversionError(value, "uint16", internal.ErrOverflow)
 		}
 		return uint16(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		i, err := bigToUint64(value, "uint16", 16)
+		if err != nil {
+			return 0, err
+		}
+		return uint16(i), nil
+	case json.Number:
+		i, err := jsonNumberToUint64(v, "uint16", 16)
+		if err != nil {
+			return 0, err
+		}
+		return uint16(i), nil
 	case bool:
 		if v {
 			return 1, nil
@@ -398,6 +466,12 @@ func ToUint16E(value interface{}) (uint16, error) {
 		}
 		return uint16(u), nil
 	default:
+		if dv, ok, err := driverValue(value, "uint16"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToUint16E(dv)
+		}
 		return 0, internal.NewConversionError(value, "uint16", internal.ErrUnsupportedType)
 	}
 }
@@ -491,6 +565,18 @@ func ToUint8E(value interface{}) (uint8, error) {
 			return 0, internal.NewConversionError(value, "uint8", internal.ErrOverflow)
 		}
 		return uint8(f), nil
+	case *big.Int, *big.Float, *big.Rat, Decimal:
+		i, err := bigToUint64(value, "uint8", 8)
+		if err != nil {
+			return 0, err
+		}
+		return uint8(i), nil
+	case json.Number:
+		i, err := jsonNumberToUint64(v, "uint8", 8)
+		if err != nil {
+			return 0, err
+		}
+		return uint8(i), nil
 	case bool:
 		if v {
 			return 1, nil
@@ -504,6 +590,12 @@ func ToUint8E(value interface{}) (uint8, error) {
 		}
 		return uint8(u), nil
 	default:
+		if dv, ok, err := driverValue(value, "uint8"); ok {
+			if err != nil {
+				return 0, err
+			}
+			return ToUint8E(dv)
+		}
 		return 0, internal.NewConversionError(value, "uint8", internal.ErrUnsupportedType)
 	}
 }
@@ -0,0 +1,34 @@
+package basic
+
+import (
+	"database/sql/driver"
+	"reflect"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// driverValue unwraps value if it implements driver.Valuer, such as the sql.Null* types,
+// or internal.Wrapper, such as complex.Var. The second result reports whether value
+// was unwrapped. Invalid Null values and nil pointers unwrap to nil, so converters
+// treat them like a nil input.
+func driverValue(value interface{}, targetType string) (interface{}, bool, error) {
+	v, ok := internal.Unwrap(value)
+	if !ok {
+		valuer, isValuer := value.(driver.Valuer)
+		if !isValuer {
+			return nil, false, nil
+		}
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, true, nil
+		}
+		var err error
+		if v, err = valuer.Value(); err != nil {
+			return nil, true, internal.NewConversionError(value, targetType, err)
+		}
+	}
+	// Guard against values that return themselves, which would recurse forever.
+	if v != nil && reflect.TypeOf(v) == reflect.TypeOf(value) {
+		return nil, true, internal.NewConversionError(value, targetType, internal.ErrUnsupportedType)
+	}
+	return v, true, nil
+}
@@ -0,0 +1,192 @@
+package basic_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/graingo/mconv"
+)
+
+func mustBigInt(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		panic("invalid big.Int literal " + s)
+	}
+	return i
+}
+
+func TestToBigIntE(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected string
+		isErr    bool
+	}{
+		{"115792089237316195423570985008687907853269984665640564039457584007913129639935", "115792089237316195423570985008687907853269984665640564039457584007913129639935", false},
+		{"0xffffffffffffffffffffffffffffffff", "340282366920938463463374607431768211455", false},
+		{"1.5e3", "1500", false},
+		{" -42 ", "-42", false},
+		{int64(-7), "-7", false},
+		{uint64(18446744073709551615), "18446744073709551615", false},
+		{12.9, "12", false},
+		{big.NewFloat(3.99), "3", false},
+		{big.NewRat(7, 2), "3", false},
+		{(*big.Int)(nil), "<nil>", false},
+		{(*big.Float)(nil), "<nil>", false},
+		{(*big.Rat)(nil), "<nil>", false},
+		{true, "1", false},
+		{"1.5", "", true},
+		{"abc", "", true},
+		{struct{}{}, "", true},
+	}
+
+	for _, test := range tests {
+		got, err := mconv.ToBigIntE(test.input)
+		if test.isErr {
+			if err == nil {
+				t.Errorf("mconv.ToBigIntE(%v) expected error", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("mconv.ToBigIntE(%v) unexpected error: %v", test.input, err)
+			continue
+		}
+		if got.String() != test.expected {
+			t.Errorf("mconv.ToBigIntE(%v) = %v; want %v", test.input, got, test.expected)
+		}
+	}
+
+	if got := mconv.ToBigInt(nil); got != nil {
+		t.Errorf("mconv.ToBigInt(nil) = %v; want nil", got)
+	}
+}
+
+func TestToBigFloatE(t *testing.T) {
+	got, err := mconv.ToBigFloatE("123456789012345678901234567890.125")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := got.Text('f', -1); s != "123456789012345678901234567890.125" {
+		t.Errorf("mconv.ToBigFloatE() = %s; want 123456789012345678901234567890.125", s)
+	}
+
+	got, err = mconv.ToBigFloatE("1e400")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.IsInf() || got.MantExp(nil) < 1000 {
+		t.Errorf("mconv.ToBigFloatE(1e400) = %v; want finite large value", got)
+	}
+
+	got, err = mconv.ToBigFloatE("0x1p-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f, _ := got.Float64(); f != 0.25 {
+		t.Errorf("mconv.ToBigFloatE(0x1p-2) = %v; want 0.25", f)
+	}
+
+	if _, err := mconv.ToBigFloatE("not a number"); err == nil {
+		t.Error("expected error for invalid string")
+	}
+
+	for _, input := range []interface{}{(*big.Int)(nil), (*big.Float)(nil), (*big.Rat)(nil)} {
+		if got, err := mconv.ToBigFloatE(input); got != nil || err != nil {
+			t.Errorf("mconv.ToBigFloatE(%T nil) = %v, %v; want nil, nil", input, got, err)
+		}
+	}
+}
+
+func TestToBigRatE(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected string
+		isErr    bool
+	}{
+		{"1/3", "1/3", false},
+		{"19.99", "1999/100", false},
+		{"2.5e-1", "1/4", false},
+		{0.5, "1/2", false},
+		{mustBigInt("10"), "10", false},
+		{(*big.Int)(nil), "<nil>", false},
+		{(*big.Float)(nil), "<nil>", false},
+		{(*big.Rat)(nil), "<nil>", false},
+		{"x", "", true},
+	}
+
+	for _, test := range tests {
+		got, err := mconv.ToBigRatE(test.input)
+		if test.isErr {
+			if err == nil {
+				t.Errorf("mconv.ToBigRatE(%v) expected error", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("mconv.ToBigRatE(%v) unexpected error: %v", test.input, err)
+			continue
+		}
+		if test.expected == "<nil>" {
+			if got != nil {
+				t.Errorf("mconv.ToBigRatE(%T nil) = %v; want nil", test.input, got.RatString())
+			}
+			continue
+		}
+		if got.RatString() != test.expected {
+			t.Errorf("mconv.ToBigRatE(%v) = %v; want %v", test.input, got.RatString(), test.expected)
+		}
+	}
+}
+
+func TestBigInputs(t *testing.T) {
+	huge := mustBigInt("115792089237316195423570985008687907853269984665640564039457584007913129639935")
+
+	if got, err := mconv.ToInt64E(big.NewInt(42)); err != nil || got != 42 {
+		t.Errorf("mconv.ToInt64E(big.Int 42) = %v, %v; want 42", got, err)
+	}
+	if _, err := mconv.ToInt64E(huge); err == nil {
+		t.Error("expected overflow error for mconv.ToInt64E(huge)")
+	}
+	if _, err := mconv.ToInt8E(big.NewInt(128)); err == nil {
+		t.Error("expected overflow error for mconv.ToInt8E(128)")
+	}
+	if got, err := mconv.ToInt8E(big.NewInt(-128)); err != nil || got != -128 {
+		t.Errorf("mconv.ToInt8E(big.Int -128) = %v, %v; want -128", got, err)
+	}
+	if got, err := mconv.ToUint64E(mustBigInt("18446744073709551615")); err != nil || got != 18446744073709551615 {
+		t.Errorf("mconv.ToUint64E(max uint64) = %v, %v", got, err)
+	}
+	if _, err := mconv.ToUintE(big.NewInt(-1)); err == nil {
+		t.Error("expected overflow error for mconv.ToUintE(-1)")
+	}
+	if _, err := mconv.ToUint16E(big.NewRat(65536, 1)); err == nil {
+		t.Error("expected overflow error for mconv.ToUint16E(65536)")
+	}
+	if got, err := mconv.ToIntE(big.NewFloat(-3.7)); err != nil || got != -3 {
+		t.Errorf("mconv.ToIntE(big.Float -3.7) = %v, %v; want -3", got, err)
+	}
+	if got, err := mconv.ToFloat64E(big.NewRat(1, 4)); err != nil || got != 0.25 {
+		t.Errorf("mconv.ToFloat64E(big.Rat 1/4) = %v, %v; want 0.25", got, err)
+	}
+	if got, err := mconv.ToFloat64E(huge); err != nil || got < 1e77 {
+		t.Errorf("mconv.ToFloat64E(huge) = %v, %v", got, err)
+	}
+	if _, err := mconv.ToFloat32E(huge); err == nil {
+		t.Error("expected overflow error for mconv.ToFloat32E(huge)")
+	}
+	if got, err := mconv.ToBoolE(big.NewInt(0)); err != nil || got {
+		t.Errorf("mconv.ToBoolE(big.Int 0) = %v, %v; want false", got, err)
+	}
+	if got := mconv.ToString(huge); got != huge.String() {
+		t.Errorf("mconv.ToString(huge) = %v; want %v", got, huge.String())
+	}
+	if got := mconv.ToString(big.NewRat(3, 1)); got != "3" {
+		t.Errorf("mconv.ToString(big.Rat 3) = %v; want 3", got)
+	}
+	if got := mconv.ToString(big.NewFloat(0.125)); got != "0.125" {
+		t.Errorf("mconv.ToString(big.Float 0.125) = %v; want 0.125", got)
+	}
+	if got := mconv.ToComplex128(big.NewInt(2)); got != complex(2, 0) {
+		t.Errorf("mconv.ToComplex128(big.Int 2) = %v; want (2+0i)", got)
+	}
+}
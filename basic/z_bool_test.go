@@ -1,6 +1,7 @@
 package basic_test
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/graingo/mconv"
@@ -67,3 +68,128 @@ func TestToBoolE(t *testing.T) {
 		}
 	}
 }
+
+func TestToBoolEVocabulary(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"on", true},
+		{"OFF", false},
+		{"Enabled", true},
+		{"disabled", false},
+		{"t", true},
+		{"F", false},
+		{"  yes\n", true},
+		{"\tNo ", false},
+	}
+
+	for _, test := range tests {
+		got, err := mconv.ToBoolE(test.input)
+		if err != nil {
+			t.Errorf("mconv.ToBoolE(%q) unexpected error: %v", test.input, err)
+		}
+		if got != test.expected {
+			t.Errorf("mconv.ToBoolE(%q) = %v; want %v", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestRegisterBoolWords(t *testing.T) {
+	defer mconv.ResetBoolWords()
+
+	if _, err := mconv.ToBoolE("是"); err == nil {
+		t.Fatal("expected error before registering localized words")
+	}
+
+	mconv.RegisterBoolWords(true, "是", "Ja", "oui")
+	mconv.RegisterBoolWords(false, "否", "nein", "NON")
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"是", true},
+		{"否", false},
+		{"ja", true},
+		{"NEIN", false},
+		{" Oui ", true},
+		{"non", false},
+	}
+	for _, test := range tests {
+		got, err := mconv.ToBoolE(test.input)
+		if err != nil {
+			t.Errorf("mconv.ToBoolE(%q) unexpected error: %v", test.input, err)
+		}
+		if got != test.expected {
+			t.Errorf("mconv.ToBoolE(%q) = %v; want %v", test.input, got, test.expected)
+		}
+	}
+
+	mconv.UnregisterBoolWords("ja")
+	if _, err := mconv.ToBoolE("ja"); err == nil {
+		t.Error("expected error after unregistering word")
+	}
+
+	mconv.ResetBoolWords()
+	if _, err := mconv.ToBoolE("oui"); err == nil {
+		t.Error("expected error after resetting vocabulary")
+	}
+}
+
+func TestResetBoolWordsConcurrent(t *testing.T) {
+	defer mconv.ResetBoolWords()
+
+	// Readers must never see the vocabulary half reset.
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			mconv.RegisterBoolWords(true, "oui")
+			mconv.ResetBoolWords()
+		}
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			wg.Wait()
+			return
+		default:
+		}
+		if got, err := mconv.ToBoolE("enabled"); err != nil || !got {
+			t.Fatalf("mconv.ToBoolE(\"enabled\") = %v, %v during reset", got, err)
+		}
+	}
+}
+
+func TestToBoolStrictE(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected bool
+		isErr    bool
+	}{
+		{true, true, false},
+		{"true", true, false},
+		{" FALSE ", false, false},
+		{nil, false, false},
+		{"yes", false, true},
+		{"1", false, true},
+		{1, false, true},
+	}
+
+	for _, test := range tests {
+		got, err := mconv.ToBoolStrictE(test.input)
+		if test.isErr && err == nil {
+			t.Errorf("mconv.ToBoolStrictE(%v) expected error", test.input)
+		}
+		if !test.isErr && err != nil {
+			t.Errorf("mconv.ToBoolStrictE(%v) unexpected error: %v", test.input, err)
+		}
+		if !test.isErr && got != test.expected {
+			t.Errorf("mconv.ToBoolStrictE(%v) = %v; want %v", test.input, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package basic_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/graingo/mconv"
+)
+
+func TestToDecimalE(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected string
+		isErr    bool
+	}{
+		{"19.99", "19.99", false},
+		{" -0.50 ", "-0.50", false},
+		{"1.25e3", "1250", false},
+		{"1.5e-3", "0.0015", false},
+		{".5", "0.5", false},
+		{19.99, "19.99", false},
+		{float32(0.1), "0.1", false},
+		{42, "42", false},
+		{uint8(7), "7", false},
+		{json.Number("123456789012345678901234567890.01"), "123456789012345678901234567890.01", false},
+		{big.NewInt(-5), "-5", false},
+		{big.NewRat(1, 8), "0.125", false},
+		{big.NewFloat(2.5), "2.5", false},
+		{mconv.NewDecimal(1999, 2), "19.99", false},
+		{nil, "0", false},
+		{big.NewRat(1, 3), "", true},
+		{"1.2.3", "", true},
+		{"abc", "", true},
+		{"", "", true},
+		{"1e999999999", "", true},
+		{struct{}{}, "", true},
+	}
+
+	for _, test := range tests {
+		got, err := mconv.ToDecimalE(test.input)
+		if test.isErr {
+			if err == nil {
+				t.Errorf("mconv.ToDecimalE(%v) expected error", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("mconv.ToDecimalE(%v) unexpected error: %v", test.input, err)
+			continue
+		}
+		if got.String() != test.expected {
+			t.Errorf("mconv.ToDecimalE(%v) = %v; want %v", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	price := mconv.ToDecimal("19.99")
+	total := price.Mul(mconv.ToDecimal(3)).Add(mconv.ToDecimal("0.03"))
+	if total.String() != "60.00" {
+		t.Errorf("total = %v; want 60.00", total)
+	}
+	if !total.Equal(mconv.ToDecimal("60")) {
+		t.Errorf("expected %v to equal 60", total)
+	}
+	if got := total.Sub(mconv.ToDecimal("0.001")).String(); got != "59.999" {
+		t.Errorf("Sub = %v; want 59.999", got)
+	}
+	if got := mconv.ToDecimal("-1.5").Abs().Neg().String(); got != "-1.5" {
+		t.Errorf("Abs().Neg() = %v; want -1.5", got)
+	}
+	if mconv.ToDecimal("1.10").Cmp(mconv.ToDecimal("1.1")) != 0 {
+		t.Error("expected 1.10 == 1.1")
+	}
+	if mconv.ToDecimal("-2").Cmp(mconv.ToDecimal("1")) != -1 {
+		t.Error("expected -2 < 1")
+	}
+
+	share, err := mconv.ToDecimal("100").DivRound(mconv.ToDecimal("3"), 2, mconv.RoundHalfEven)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if share.String() != "33.33" {
+		t.Errorf("DivRound = %v; want 33.33", share)
+	}
+	if _, err := share.DivRound(mconv.Decimal{}, 2, mconv.RoundHalfUp); err == nil {
+		t.Error("expected division by zero error")
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		input    string
+		places   int32
+		mode     mconv.RoundingMode
+		expected string
+	}{
+		{"2.345", 2, mconv.RoundHalfUp, "2.35"},
+		{"-2.345", 2, mconv.RoundHalfUp, "-2.35"},
+		{"2.345", 2, mconv.RoundHalfEven, "2.34"},
+		{"2.355", 2, mconv.RoundHalfEven, "2.36"},
+		{"2.345", 2, mconv.RoundHalfDown, "2.34"},
+		{"2.3451", 2, mconv.RoundHalfDown, "2.35"},
+		{"2.341", 2, mconv.RoundUp, "2.35"},
+		{"-2.341", 2, mconv.RoundUp, "-2.35"},
+		{"2.349", 2, mconv.RoundDown, "2.34"},
+		{"-2.341", 2, mconv.RoundCeiling, "-2.34"},
+		{"2.341", 2, mconv.RoundCeiling, "2.35"},
+		{"-2.341", 2, mconv.RoundFloor, "-2.35"},
+		{"0.004", 2, mconv.RoundHalfUp, "0.00"},
+		{"1.5", 3, mconv.RoundHalfUp, "1.500"},
+		{"9.99", 0, mconv.RoundHalfUp, "10"},
+	}
+
+	for _, test := range tests {
+		got := mconv.ToDecimal(test.input).Round(test.places, test.mode).String()
+		if got != test.expected {
+			t.Errorf("Round(%s, %d, %d) = %s; want %s", test.input, test.places, test.mode, got, test.expected)
+		}
+	}
+}
+
+func TestDecimalConversions(t *testing.T) {
+	d := mconv.ToDecimal("19.99")
+
+	if got := mconv.ToString(d); got != "19.99" {
+		t.Errorf("mconv.ToString(Decimal) = %v; want 19.99", got)
+	}
+	if got := mconv.ToFloat64(d); got != 19.99 {
+		t.Errorf("mconv.ToFloat64(Decimal) = %v; want 19.99", got)
+	}
+	if got := mconv.ToInt(d); got != 19 {
+		t.Errorf("mconv.ToInt(Decimal) = %v; want 19", got)
+	}
+	if _, err := mconv.ToUint8E(mconv.ToDecimal("256.1")); err == nil {
+		t.Error("expected overflow error for mconv.ToUint8E(256.1)")
+	}
+	if got := mconv.ToBool(mconv.Decimal{}); got {
+		t.Error("mconv.ToBool(zero Decimal) = true; want false")
+	}
+	if got := mconv.ToBigRat(d).RatString(); got != "1999/100" {
+		t.Errorf("mconv.ToBigRat(Decimal) = %v; want 1999/100", got)
+	}
+
+	data, err := json.Marshal(map[string]mconv.Decimal{"total": d})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"total":"19.99"}` {
+		t.Errorf("json.Marshal = %s", data)
+	}
+
+	var decoded struct {
+		A mconv.Decimal `json:"a"`
+		B mconv.Decimal `json:"b"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":"0.10","b":12.345}`), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.A.String() != "0.10" || decoded.B.String() != "12.345" {
+		t.Errorf("json.Unmarshal = %v, %v", decoded.A, decoded.B)
+	}
+}
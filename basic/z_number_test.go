@@ -0,0 +1,90 @@
+package basic_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/graingo/mconv"
+)
+
+func TestJSONNumberIntegers(t *testing.T) {
+	// 2^53 + 1 cannot be represented exactly as a float64.
+	n := json.Number("9007199254740993")
+
+	if got, err := mconv.ToInt64E(n); err != nil || got != 9007199254740993 {
+		t.Errorf("mconv.ToInt64E(%v) = %v, %v; want 9007199254740993", n, got, err)
+	}
+	if got, err := mconv.ToIntE(n); err != nil || got != 9007199254740993 {
+		t.Errorf("mconv.ToIntE(%v) = %v, %v; want 9007199254740993", n, got, err)
+	}
+	if got, err := mconv.ToUint64E(json.Number("18446744073709551615")); err != nil || got != 18446744073709551615 {
+		t.Errorf("mconv.ToUint64E(max uint64) = %v, %v", got, err)
+	}
+
+	tests := []struct {
+		input    json.Number
+		expected int64
+		isErr    bool
+	}{
+		{"42", 42, false},
+		{"-7", -7, false},
+		{"1e3", 1000, false},
+		{"12.9", 12, false},
+		{"-12.9", -12, false},
+		{"9223372036854775808", 0, true},
+		{"abc", 0, true},
+	}
+	for _, test := range tests {
+		got, err := mconv.ToInt64E(test.input)
+		if test.isErr {
+			if err == nil {
+				t.Errorf("mconv.ToInt64E(%v) expected error", test.input)
+			}
+			continue
+		}
+		if err != nil || got != test.expected {
+			t.Errorf("mconv.ToInt64E(%v) = %v, %v; want %v", test.input, got, err, test.expected)
+		}
+	}
+
+	if _, err := mconv.ToInt8E(json.Number("128")); err == nil {
+		t.Error("expected overflow error for mconv.ToInt8E(128)")
+	}
+	if _, err := mconv.ToUintE(json.Number("-1")); err == nil {
+		t.Error("expected overflow error for mconv.ToUintE(-1)")
+	}
+	if got, err := mconv.ToUint16E(json.Number("6.5e4")); err != nil || got != 65000 {
+		t.Errorf("mconv.ToUint16E(6.5e4) = %v, %v; want 65000", got, err)
+	}
+}
+
+func TestJSONNumberOthers(t *testing.T) {
+	if got, err := mconv.ToFloat64E(json.Number("19.99")); err != nil || got != 19.99 {
+		t.Errorf("mconv.ToFloat64E(19.99) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToFloat32E(json.Number("0.5")); err != nil || got != 0.5 {
+		t.Errorf("mconv.ToFloat32E(0.5) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToBoolE(json.Number("0.0")); err != nil || got {
+		t.Errorf("mconv.ToBoolE(0.0) = %v, %v; want false", got, err)
+	}
+	if got, err := mconv.ToBoolE(json.Number("2")); err != nil || !got {
+		t.Errorf("mconv.ToBoolE(2) = %v, %v; want true", got, err)
+	}
+	if got, err := mconv.ToComplex128E(json.Number("1.5")); err != nil || got != complex(1.5, 0) {
+		t.Errorf("mconv.ToComplex128E(1.5) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToDurationE(json.Number("1500")); err != nil || got != 1500*time.Nanosecond {
+		t.Errorf("mconv.ToDurationE(1500) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToTimeE(json.Number("1700000000")); err != nil || got.Unix() != 1700000000 {
+		t.Errorf("mconv.ToTimeE(1700000000) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToBigIntE(json.Number("123456789012345678901234567890")); err != nil || got.String() != "123456789012345678901234567890" {
+		t.Errorf("mconv.ToBigIntE() = %v, %v", got, err)
+	}
+	if got := mconv.ToString(json.Number("9007199254740993")); got != "9007199254740993" {
+		t.Errorf("mconv.ToString() = %v", got)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build go1.22
+
+package basic_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/graingo/mconv"
+)
+
+func TestValuerGenericNull(t *testing.T) {
+	if got, err := mconv.ToIntE(sql.Null[int64]{V: 9, Valid: true}); err != nil || got != 9 {
+		t.Errorf("mconv.ToIntE(Null[int64]) = %v, %v; want 9", got, err)
+	}
+	if got, err := mconv.ToStringE(sql.Null[string]{V: "x"}); err != nil || got != "" {
+		t.Errorf("mconv.ToStringE(invalid Null[string]) = %q, %v; want empty", got, err)
+	}
+}
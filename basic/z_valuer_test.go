@@ -0,0 +1,81 @@
+package basic_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graingo/mconv"
+)
+
+type centsValuer int64
+
+func (c centsValuer) Value() (driver.Value, error) {
+	return int64(c), nil
+}
+
+type failingValuer struct{}
+
+func (failingValuer) Value() (driver.Value, error) {
+	return nil, errors.New("boom")
+}
+
+func TestValuerValid(t *testing.T) {
+	if got, err := mconv.ToStringE(sql.NullString{String: "alice", Valid: true}); err != nil || got != "alice" {
+		t.Errorf("mconv.ToStringE(NullString) = %q, %v; want alice", got, err)
+	}
+	if got, err := mconv.ToInt64E(sql.NullInt64{Int64: 42, Valid: true}); err != nil || got != 42 {
+		t.Errorf("mconv.ToInt64E(NullInt64) = %v, %v; want 42", got, err)
+	}
+	if got, err := mconv.ToIntE(sql.NullInt32{Int32: 7, Valid: true}); err != nil || got != 7 {
+		t.Errorf("mconv.ToIntE(NullInt32) = %v, %v; want 7", got, err)
+	}
+	if got, err := mconv.ToFloat64E(sql.NullFloat64{Float64: 1.5, Valid: true}); err != nil || got != 1.5 {
+		t.Errorf("mconv.ToFloat64E(NullFloat64) = %v, %v; want 1.5", got, err)
+	}
+	if got, err := mconv.ToBoolE(sql.NullBool{Bool: true, Valid: true}); err != nil || !got {
+		t.Errorf("mconv.ToBoolE(NullBool) = %v, %v; want true", got, err)
+	}
+	if got, err := mconv.ToIntE(sql.NullString{String: "12", Valid: true}); err != nil || got != 12 {
+		t.Errorf("mconv.ToIntE(NullString 12) = %v, %v; want 12", got, err)
+	}
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got, err := mconv.ToTimeE(sql.NullTime{Time: now, Valid: true}); err != nil || !got.Equal(now) {
+		t.Errorf("mconv.ToTimeE(NullTime) = %v, %v; want %v", got, err, now)
+	}
+
+	if got, err := mconv.ToUint8E(centsValuer(200)); err != nil || got != 200 {
+		t.Errorf("mconv.ToUint8E(centsValuer) = %v, %v; want 200", got, err)
+	}
+	if got := mconv.ToDecimal(centsValuer(1999)).String(); got != "1999" {
+		t.Errorf("mconv.ToDecimal(centsValuer) = %v; want 1999", got)
+	}
+	if got := mconv.ToString(&sql.NullInt64{Int64: 5, Valid: true}); got != "5" {
+		t.Errorf("mconv.ToString(*NullInt64) = %v; want 5", got)
+	}
+}
+
+func TestValuerInvalid(t *testing.T) {
+	if got, err := mconv.ToStringE(sql.NullString{String: "ignored"}); err != nil || got != "" {
+		t.Errorf("mconv.ToStringE(invalid NullString) = %q, %v; want empty", got, err)
+	}
+	if got, err := mconv.ToInt64E(sql.NullInt64{Int64: 42}); err != nil || got != 0 {
+		t.Errorf("mconv.ToInt64E(invalid NullInt64) = %v, %v; want 0", got, err)
+	}
+	if got, err := mconv.ToTimeE(sql.NullTime{}); err != nil || !got.IsZero() {
+		t.Errorf("mconv.ToTimeE(invalid NullTime) = %v, %v; want zero", got, err)
+	}
+	var nilPtr *sql.NullInt64
+	if got, err := mconv.ToIntE(nilPtr); err != nil || got != 0 {
+		t.Errorf("mconv.ToIntE(nil *NullInt64) = %v, %v; want 0", got, err)
+	}
+	if _, err := mconv.ToIntE(failingValuer{}); err == nil {
+		t.Error("expected error from failing Valuer")
+	}
+	if _, err := mconv.ToStringE(failingValuer{}); err == nil {
+		t.Error("expected error from failing Valuer")
+	}
+}
@@ -0,0 +1,284 @@
+package complex
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// IndexStyle controls how Flatten and UnflattenE write and read slice indices.
+type IndexStyle int
+
+const (
+	// IndexSep writes indices as path segments joined by the separator, as in
+	// "ports.0". UnflattenE turns nodes whose keys are exactly 0 to n-1 into slices.
+	IndexSep IndexStyle = iota
+	// IndexBrackets writes indices in brackets after their parent, as in "ports[0]".
+	// UnflattenE only turns bracketed indices into slices, so "ports.0" stays a map key.
+	IndexBrackets
+	// IndexNone leaves slices as values. UnflattenE never creates slices.
+	IndexNone
+)
+
+// FlattenOptions configures FlattenWithOptions and UnflattenWithOptionsE.
+type FlattenOptions struct {
+	// Sep joins the keys of nested maps, such as "." or "__".
+	Sep string
+	// IndexStyle is the format of slice indices.
+	IndexStyle IndexStyle
+}
+
+// Flatten converts nested maps into a single-level map whose keys are the
+// paths to each leaf joined by sep. Slices and arrays are flattened with their
+// element index as the path segment, for example:
+//
+//	Flatten(map[string]interface{}{"db": map[string]interface{}{"host": "x", "ports": []int{1, 2}}}, ".")
+//	// map[string]interface{}{"db.host": "x", "db.ports.0": 1, "db.ports.1": 2}
+//
+// Empty maps and slices are kept as values so that Unflatten can restore them.
+// See FlattenWithOptions for other index styles.
+func Flatten(m map[string]interface{}, sep string) map[string]interface{} {
+	return FlattenWithOptions(m, FlattenOptions{Sep: sep})
+}
+
+// FlattenWithOptions flattens nested maps like Flatten, joining keys with opts.Sep
+// and writing slice indices according to opts.IndexStyle.
+func FlattenWithOptions(m map[string]interface{}, opts FlattenOptions) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		flattenInto(result, k, v, opts)
+	}
+	return result
+}
+
+// flattenInto writes the leaves of value into result, prefixing their keys with prefix.
+func flattenInto(result map[string]interface{}, prefix string, value interface{}, opts FlattenOptions) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Len() == 0 {
+			break
+		}
+		m, err := ToMapE(value)
+		if err != nil {
+			break
+		}
+		for k, v := range m {
+			flattenInto(result, prefix+opts.Sep+k, v, opts)
+		}
+		return
+	case reflect.Slice, reflect.Array:
+		// Byte slices are values, not lists.
+		if opts.IndexStyle == IndexNone || rv.Len() == 0 || rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		for i := 0; i < rv.Len(); i++ {
+			key := prefix + opts.Sep + strconv.Itoa(i)
+			if opts.IndexStyle == IndexBrackets {
+				key = prefix + "[" + strconv.Itoa(i) + "]"
+			}
+			flattenInto(result, key, rv.Index(i).Interface(), opts)
+		}
+		return
+	}
+	result[prefix] = value
+}
+
+// Unflatten is the inverse of Flatten; conflicting keys are ignored.
+func Unflatten(m map[string]interface{}, sep string) map[string]interface{} {
+	result, _ := UnflattenE(m, sep)
+	return result
+}
+
+// UnflattenE rebuilds nested maps from keys joined by sep, with error.
+// Nodes whose keys are exactly the indices 0 to n-1 become []interface{}.
+// Keys that are both a leaf and a parent, such as "a" and "a.b", are reported as errors,
+// except when the leaf is itself a map[string]interface{}, which is merged.
+// See UnflattenWithOptionsE for other index styles.
+func UnflattenE(m map[string]interface{}, sep string) (map[string]interface{}, error) {
+	return UnflattenWithOptionsE(m, FlattenOptions{Sep: sep})
+}
+
+// UnflattenWithOptions is the inverse of FlattenWithOptions; conflicting keys are ignored.
+func UnflattenWithOptions(m map[string]interface{}, opts FlattenOptions) map[string]interface{} {
+	result, _ := UnflattenWithOptionsE(m, opts)
+	return result
+}
+
+// UnflattenWithOptionsE rebuilds nested maps like UnflattenE, splitting keys on opts.Sep
+// and reading slice indices according to opts.IndexStyle, with error. With IndexBrackets,
+// the bracketed indices of a node must be exactly 0 to n-1 and cannot be mixed with keys.
+func UnflattenWithOptionsE(m map[string]interface{}, opts FlattenOptions) (map[string]interface{}, error) {
+	if m == nil {
+		return nil, nil
+	}
+	if opts.Sep == "" {
+		return nil, internal.NewConversionError(opts.Sep, "separator", internal.ErrInvalidFormat)
+	}
+
+	// Sort keys so that conflicts are reported deterministically.
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	root := flatNode{}
+	for _, key := range keys {
+		path := strings.Split(key, opts.Sep)
+		if opts.IndexStyle == IndexBrackets {
+			path = splitBracketIndices(path)
+		}
+		if err := root.insert(path, m[key]); err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+	}
+	return root.toMap(opts.IndexStyle)
+}
+
+// splitBracketIndices splits trailing bracketed indices off each segment, so that
+// "ports[0][1]" becomes "ports", "[0]" and "[1]".
+func splitBracketIndices(path []string) []string {
+	result := make([]string, 0, len(path))
+	for _, segment := range path {
+		var indices []string
+		for strings.HasSuffix(segment, "]") {
+			open := strings.LastIndexByte(segment, '[')
+			if open <= 0 || !isIndex(segment[open+1:len(segment)-1]) {
+				break
+			}
+			indices = append(indices, segment[open:])
+			segment = segment[:open]
+		}
+		result = append(result, segment)
+		for i := len(indices) - 1; i >= 0; i-- {
+			result = append(result, indices[i])
+		}
+	}
+	return result
+}
+
+// isIndex reports whether s is a non-negative decimal index without leading zeros.
+func isIndex(s string) bool {
+	i, err := strconv.Atoi(s)
+	return err == nil && i >= 0 && strconv.Itoa(i) == s
+}
+
+// flatNode is an intermediate map built by UnflattenE.
+// It is kept distinct from map values present in the input, which are left as-is.
+type flatNode map[string]interface{}
+
+// insert stores value at the given path, creating intermediate nodes.
+func (n flatNode) insert(path []string, value interface{}) error {
+	key := path[0]
+	existing, ok := n[key]
+	if len(path) == 1 {
+		if !ok {
+			n[key] = value
+			return nil
+		}
+		child, isNode := existing.(flatNode)
+		m, isMap := value.(map[string]interface{})
+		if !isNode || !isMap {
+			return fmt.Errorf("conflicting values for %q", key)
+		}
+		for k, v := range m {
+			if err := child.insert([]string{k}, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	child, isNode := existing.(flatNode)
+	if !isNode {
+		if ok {
+			m, isMap := existing.(map[string]interface{})
+			if !isMap {
+				return fmt.Errorf("%q is both a value and a parent", key)
+			}
+			child = make(flatNode, len(m))
+			for k, v := range m {
+				child[k] = v
+			}
+		} else {
+			child = flatNode{}
+		}
+		n[key] = child
+	}
+	return child.insert(path[1:], value)
+}
+
+// toMap converts the node and its descendants into map[string]interface{}.
+func (n flatNode) toMap(style IndexStyle) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(n))
+	for k, v := range n {
+		if child, ok := v.(flatNode); ok {
+			value, err := child.toValue(style)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", k, err)
+			}
+			result[k] = value
+		} else {
+			result[k] = v
+		}
+	}
+	return result, nil
+}
+
+// toValue converts the node like toMap, but returns []interface{} when its keys
+// are slice indices in the given style.
+func (n flatNode) toValue(style IndexStyle) (interface{}, error) {
+	result, err := n.toMap(style)
+	if err != nil || len(result) == 0 || style == IndexNone {
+		return result, err
+	}
+	if style == IndexBrackets {
+		return bracketSlice(result)
+	}
+
+	slice := make([]interface{}, len(result))
+	for k, v := range result {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(slice) || strconv.Itoa(i) != k {
+			return result, nil
+		}
+		slice[i] = v
+	}
+	return slice, nil
+}
+
+// bracketSlice returns m as []interface{} when its keys are the bracketed indices
+// [0] to [n-1], and m itself when it has no bracketed keys.
+func bracketSlice(m map[string]interface{}) (interface{}, error) {
+	indices := 0
+	for k := range m {
+		if strings.HasPrefix(k, "[") {
+			indices++
+		}
+	}
+	if indices == 0 {
+		return m, nil
+	}
+	if indices != len(m) {
+		return nil, errors.New("slice indices mixed with map keys")
+	}
+
+	slice := make([]interface{}, len(m))
+	for k, v := range m {
+		i, err := strconv.Atoi(k[1 : len(k)-1])
+		if err != nil || i >= len(slice) {
+			return nil, fmt.Errorf("index %s out of range: indices must run from [0] to [%d]", k, len(slice)-1)
+		}
+		slice[i] = v
+	}
+	return slice, nil
+}
@@ -1,12 +1,19 @@
 package complex
 
 import (
+	"encoding/json"
+	"math/big"
 	"reflect"
 	"time"
 
 	"github.com/graingo/mconv/basic"
 )
 
+// defaultHooks returns the hooks that are always applied before user-provided hooks.
+func defaultHooks() []HookFunc {
+	return []HookFunc{stringToTimeHookFunc(), stringToDurationHookFunc(), intToBoolHookFunc(), bigNumberHookFunc()}
+}
+
 // stringToTimeHookFunc returns a HookFunc that converts string to time.Time.
 func stringToTimeHookFunc() HookFunc {
 	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
@@ -15,13 +22,14 @@ func stringToTimeHookFunc() HookFunc {
 			return data, nil
 		}
 
-		s, ok := data.(string)
-		if !ok {
-			return data, nil
+		switch v := data.(type) {
+		case string:
+			// Use the existing ToTimeE for conversion.
+			return basic.ToTimeE(v)
+		case json.Number:
+			return basic.ToTimeE(v)
 		}
-
-		// Use the existing ToTimeE for conversion.
-		return basic.ToTimeE(s)
+		return data, nil
 	}
 }
 
@@ -63,3 +71,32 @@ func intToBoolHookFunc() HookFunc {
 		return data, nil
 	}
 }
+
+var (
+	bigIntType   = reflect.TypeOf((*big.Int)(nil))
+	bigFloatType = reflect.TypeOf((*big.Float)(nil))
+	bigRatType   = reflect.TypeOf((*big.Rat)(nil))
+	decimalType  = reflect.TypeOf(basic.Decimal{})
+)
+
+// bigNumberHookFunc returns a HookFunc that converts numbers and strings to
+// *big.Int, *big.Float, *big.Rat and basic.Decimal.
+func bigNumberHookFunc() HookFunc {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from == to {
+			return data, nil
+		}
+
+		switch to {
+		case bigIntType:
+			return basic.ToBigIntE(data)
+		case bigFloatType:
+			return basic.ToBigFloatE(data)
+		case bigRatType:
+			return basic.ToBigRatE(data)
+		case decimalType:
+			return basic.ToDecimalE(data)
+		}
+		return data, nil
+	}
+}
@@ -0,0 +1,138 @@
+package complex
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// INIError describes invalid INI input. It matches internal.ErrInvalidINIFormat
+// with errors.Is and unwraps to the underlying error.
+type INIError struct {
+	// Err describes the problem.
+	Err error
+	// Line is the 1-based line of the problem in the input.
+	Line int
+}
+
+// Error implements the error interface.
+func (e *INIError) Error() string {
+	return fmt.Sprintf("%v at line %d: %v", internal.ErrInvalidINIFormat, e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *INIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is internal.ErrInvalidINIFormat.
+func (e *INIError) Is(target error) bool {
+	return target == internal.ErrInvalidINIFormat
+}
+
+// ToMapFromINIE decodes INI input into map[string]interface{} with error.
+//
+// Each [section] becomes a nested map, and a dotted section name such as
+// [server.http] nests further; keys before the first section stay at the top level.
+// Keys and values are separated by = or :, and the whitespace around them and a pair
+// of matching quotes around the value are removed. Lines starting with ; or # are
+// comments. A value continues on the following lines that are indented more than
+// its key, which are joined with a line feed, and after a line ending with a
+// backslash, which is joined to the next line directly. A repeated section adds to
+// the earlier one and a repeated key replaces the earlier value. All values are
+// strings, which ToStructE converts to the field types. An empty input yields a nil map.
+func ToMapFromINIE(iniStr string) (map[string]interface{}, error) {
+	if iniStr == "" {
+		return nil, nil
+	}
+	result := make(map[string]interface{})
+	section := result
+	lines := strings.Split(strings.TrimPrefix(iniStr, "\ufeff"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSuffix(lines[i], "\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || trimmed[0] == ';' || trimmed[0] == '#':
+			continue
+		case trimmed[0] == '[':
+			if trimmed[len(trimmed)-1] != ']' {
+				return nil, newINIError(iniStr, i, "unterminated section header %q", trimmed)
+			}
+			name := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if name == "" {
+				return nil, newINIError(iniStr, i, "empty section name")
+			}
+			var err error
+			if section, err = iniSection(result, name); err != nil {
+				return nil, newINIError(iniStr, i, "%v", err)
+			}
+			continue
+		}
+
+		sep := strings.IndexAny(trimmed, "=:")
+		if sep < 0 {
+			return nil, newINIError(iniStr, i, "expected '=' or ':' after key %q", trimmed)
+		}
+		key := strings.TrimSpace(trimmed[:sep])
+		if key == "" {
+			return nil, newINIError(iniStr, i, "missing key")
+		}
+		value := strings.TrimSpace(trimmed[sep+1:])
+
+		// Collect continuation lines.
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		for i+1 < len(lines) {
+			next := strings.TrimSuffix(lines[i+1], "\r")
+			nextTrimmed := strings.TrimSpace(next)
+			if strings.HasSuffix(value, "\\") {
+				value = value[:len(value)-1] + nextTrimmed
+			} else if nextTrimmed != "" && len(next)-len(strings.TrimLeft(next, " \t")) > indent &&
+				nextTrimmed[0] != ';' && nextTrimmed[0] != '#' {
+				value += "\n" + nextTrimmed
+			} else {
+				break
+			}
+			i++
+		}
+
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		if _, ok := section[key].(map[string]interface{}); ok {
+			return nil, newINIError(iniStr, i, "key %q conflicts with a section of the same name", key)
+		}
+		section[key] = value
+	}
+	return result, nil
+}
+
+// ToMapFromINI decodes INI input into map[string]interface{}.
+func ToMapFromINI(iniStr string) map[string]interface{} {
+	result, _ := ToMapFromINIE(iniStr)
+	return result
+}
+
+// newINIError reports a syntax error on the 0-based line index of iniStr.
+func newINIError(iniStr string, index int, format string, args ...interface{}) error {
+	return internal.NewConversionError(iniStr, "map", &INIError{Err: fmt.Errorf(format, args...), Line: index + 1})
+}
+
+// iniSection returns the map for a possibly dotted section name, creating it if needed.
+func iniSection(root map[string]interface{}, name string) (map[string]interface{}, error) {
+	section := root
+	for _, part := range strings.Split(name, ".") {
+		part = strings.TrimSpace(part)
+		switch v := section[part].(type) {
+		case nil:
+			next := make(map[string]interface{})
+			section[part] = next
+			section = next
+		case map[string]interface{}:
+			section = v
+		default:
+			return nil, fmt.Errorf("section %q conflicts with key %q", name, part)
+		}
+	}
+	return section, nil
+}
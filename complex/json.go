@@ -1,54 +1,407 @@
 package complex
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 
 	"github.com/graingo/mconv/internal"
 )
 
+// JSONOption configures how JSON is decoded or encoded.
+// Options that do not apply to an operation are ignored.
+type JSONOption func(*jsonConfig)
+
+// jsonConfig holds the settings built from JSONOptions.
+type jsonConfig struct {
+	// Decoding.
+	useNumber             bool
+	disallowUnknownFields bool
+	lines                 bool
+	relaxed               bool
+
+	// Encoding.
+	prefix       string
+	indent       string
+	noEscapeHTML bool
+	sortKeys     bool
+	canonical    bool
+	complexes    ComplexEncoding
+	nonFinite    NonFiniteEncoding
+}
+
+// ComplexEncoding selects how ToJSONE encodes complex numbers.
+type ComplexEncoding int
+
+const (
+	// ComplexAsString encodes complex numbers as strings such as "(1+2i)",
+	// which ToComplex128E parses back.
+	ComplexAsString ComplexEncoding = iota
+	// ComplexAsArray encodes complex numbers as [real, imag].
+	ComplexAsArray
+	// ComplexAsObject encodes complex numbers as {"real": real, "imag": imag}.
+	ComplexAsObject
+)
+
+// NonFiniteEncoding selects how ToJSONE encodes NaN and infinite floats,
+// which JSON cannot represent as numbers.
+type NonFiniteEncoding int
+
+const (
+	// NonFiniteAsNull encodes NaN and infinities as null.
+	NonFiniteAsNull NonFiniteEncoding = iota
+	// NonFiniteAsString encodes them as the strings "NaN", "+Inf" and "-Inf",
+	// which ToFloat64E parses back.
+	NonFiniteAsString
+	// NonFiniteAsError reports them as errors, as json.Marshal does.
+	NonFiniteAsError
+)
+
+// newJSONConfig applies opts to a zero jsonConfig.
+func newJSONConfig(opts []JSONOption) jsonConfig {
+	var config jsonConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// JSONUseNumber decodes numbers into interface{} values as json.Number instead of
+// float64, so integers above 2^53 keep their precision.
+func JSONUseNumber() JSONOption {
+	return func(c *jsonConfig) {
+		c.useNumber = true
+	}
+}
+
+// JSONDisallowUnknownFields reports an error for object keys that do not match
+// any exported field of a destination struct.
+func JSONDisallowUnknownFields() JSONOption {
+	return func(c *jsonConfig) {
+		c.disallowUnknownFields = true
+	}
+}
+
+// JSONLines makes StreamJSONE treat its input as newline-delimited JSON even when
+// the first record is an array.
+func JSONLines() JSONOption {
+	return func(c *jsonConfig) {
+		c.lines = true
+	}
+}
+
+// JSONIndent makes ToJSONE indent its output like json.MarshalIndent.
+func JSONIndent(prefix, indent string) JSONOption {
+	return func(c *jsonConfig) {
+		c.prefix = prefix
+		c.indent = indent
+	}
+}
+
+// JSONDisableHTMLEscape makes ToJSONE write <, > and & as-is instead of escaping them.
+func JSONDisableHTMLEscape() JSONOption {
+	return func(c *jsonConfig) {
+		c.noEscapeHTML = true
+	}
+}
+
+// JSONSortKeys makes ToJSONE write the fields of structs, as well as map keys, in
+// sorted order, so that output does not change with field declaration order.
+func JSONSortKeys() JSONOption {
+	return func(c *jsonConfig) {
+		c.sortKeys = true
+	}
+}
+
+// JSONCanonical makes ToJSONE produce canonical JSON as defined by RFC 8785, the
+// JSON Canonicalization Scheme, so that equal values always encode to the same bytes
+// for hashing and signing. Object keys are sorted by UTF-16 code units, numbers use
+// ECMAScript serialization and strings are minimally escaped. Struct fields are named
+// as ToStructE reads them from their tags. JSONIndent, JSONSortKeys and
+// JSONDisableHTMLEscape are ignored in canonical mode.
+func JSONCanonical() JSONOption {
+	return func(c *jsonConfig) {
+		c.canonical = true
+	}
+}
+
+// JSONComplexAs sets how ToJSONE encodes complex numbers. The default is ComplexAsString.
+func JSONComplexAs(encoding ComplexEncoding) JSONOption {
+	return func(c *jsonConfig) {
+		c.complexes = encoding
+	}
+}
+
+// JSONNonFiniteAs sets how ToJSONE encodes NaN and infinite floats.
+// The default is NonFiniteAsNull.
+func JSONNonFiniteAs(encoding NonFiniteEncoding) JSONOption {
+	return func(c *jsonConfig) {
+		c.nonFinite = encoding
+	}
+}
+
+// JSONRelaxed accepts the relaxed syntax of hand-written configuration files,
+// a subset of JSON5: // and /* */ comments, trailing commas, single-quoted strings,
+// unquoted object keys, hexadecimal numbers such as 0x1F and a leading plus sign.
+// Error positions refer to the original input.
+func JSONRelaxed() JSONOption {
+	return func(c *jsonConfig) {
+		c.relaxed = true
+	}
+}
+
+// JSONError describes invalid JSON input. It matches internal.ErrInvalidJSONFormat
+// with errors.Is and unwraps to the original *json.SyntaxError or
+// *json.UnmarshalTypeError, so callers can still inspect them with errors.As.
+type JSONError struct {
+	// Err is the error returned by encoding/json.
+	Err error
+	// Offset is the byte offset in the input where the error was detected.
+	Offset int64
+	// Line and Column are the 1-based position of Offset, or zero when the input
+	// was streamed and is no longer available.
+	Line   int
+	Column int
+	// Field is the path of the struct field for type errors, if known.
+	Field string
+}
+
+// Error implements the error interface.
+func (e *JSONError) Error() string {
+	msg := fmt.Sprintf("%v at offset %d", internal.ErrInvalidJSONFormat, e.Offset)
+	if e.Line > 0 {
+		msg = fmt.Sprintf("%v at line %d, column %d", internal.ErrInvalidJSONFormat, e.Line, e.Column)
+	}
+	if e.Field != "" {
+		msg += fmt.Sprintf(", field %q", e.Field)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by encoding/json.
+func (e *JSONError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is internal.ErrInvalidJSONFormat.
+func (e *JSONError) Is(target error) bool {
+	return target == internal.ErrInvalidJSONFormat
+}
+
+// decodeJSON decodes a single JSON value from data into target.
+func decodeJSON(data []byte, target interface{}, opts []JSONOption) error {
+	config := newJSONConfig(opts)
+
+	input := data
+	var offsets []int64
+	if config.relaxed {
+		var err error
+		if input, offsets, err = relaxJSON(data); err != nil {
+			return err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	if config.useNumber {
+		decoder.UseNumber()
+	}
+	if config.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(target); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return relocateJSONError(newJSONError(input, err, decoder.InputOffset()), data, offsets)
+	}
+	// Reject trailing data, as json.Unmarshal does.
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		offset := decoder.InputOffset()
+		jsonErr := newJSONError(input, errors.New("invalid character after top-level value"), offset)
+		return relocateJSONError(jsonErr, data, offsets)
+	}
+	return nil
+}
+
+// relocateJSONError moves an error found in relaxed input translated by relaxJSON
+// back to its position in the original data. It returns err unchanged when offsets is nil.
+func relocateJSONError(err *JSONError, data []byte, offsets []int64) *JSONError {
+	if offsets == nil {
+		return err
+	}
+	switch {
+	case err.Offset <= 0:
+	case int(err.Offset) > len(offsets):
+		err.Offset = int64(len(data))
+	default:
+		err.Offset = offsets[err.Offset-1] + 1
+	}
+	err.Line, err.Column = jsonPosition(data, err.Offset)
+	return err
+}
+
+// newJSONError builds a JSONError, taking the offset from err when it provides one.
+func newJSONError(data []byte, err error, offset int64) *JSONError {
+	jsonErr := &JSONError{Err: err, Offset: offset}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		jsonErr.Offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		jsonErr.Offset = typeErr.Offset
+		jsonErr.Field = typeErr.Field
+	}
+	jsonErr.Line, jsonErr.Column = jsonPosition(data, jsonErr.Offset)
+	return jsonErr
+}
+
+// jsonPosition converts a byte offset reported by encoding/json into a line and column.
+// encoding/json reports the number of bytes read, so the offending byte is the one before.
+func jsonPosition(data []byte, offset int64) (line, column int) {
+	pos := int(offset)
+	if pos > len(data) {
+		pos = len(data)
+	}
+	if pos > 0 {
+		pos--
+	}
+	line = 1 + bytes.Count(data[:pos], []byte{'\n'})
+	column = pos - bytes.LastIndexByte(data[:pos], '\n')
+	return line, column
+}
+
+// readJSON reads all of r so that error positions can be computed.
+func readJSON(r io.Reader, targetType string) ([]byte, error) {
+	if r == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, internal.NewConversionError(r, targetType, err)
+	}
+	return data, nil
+}
+
 // ToJSONE converts any type to JSON string with error.
-func ToJSONE(value interface{}) (string, error) {
+// Values that encoding/json rejects are normalized first: maps with keys such as
+// interface{} are converted to map[string]interface{} like ToMapE, complex numbers
+// and non-finite floats are encoded as configured by JSONComplexAs and JSONNonFiniteAs,
+// and channels and functions are encoded as null.
+func ToJSONE(value interface{}, opts ...JSONOption) (string, error) {
 	if value == nil {
 		return "null", nil
 	}
+	config := newJSONConfig(opts)
+
+	if config.canonical {
+		data, err := canonicalJSON(value, config)
+		if err != nil {
+			return "", internal.NewConversionError(value, "JSON", err)
+		}
+		return string(data), nil
+	}
 
-	// use json.Marshal to convert
-	bytes, err := json.Marshal(value)
+	data, err := encodeJSON(value, config)
+	var typeErr *json.UnsupportedTypeError
+	var valueErr *json.UnsupportedValueError
+	if errors.As(err, &typeErr) || errors.As(err, &valueErr) {
+		// Only values that encoding/json rejects are normalized, so valid input
+		// is encoded exactly as json.Marshal would.
+		var normalized interface{}
+		if normalized, err = normalizeJSON(value, config); err == nil {
+			data, err = encodeJSON(normalized, config)
+		}
+	}
+	if err == nil && config.sortKeys {
+		// Round-trip through interface{} so that struct fields become sorted map keys.
+		var generic interface{}
+		if err = decodeJSON(data, &generic, []JSONOption{JSONUseNumber()}); err == nil {
+			data, err = encodeJSON(generic, config)
+		}
+	}
 	if err != nil {
 		return "", internal.NewConversionError(value, "JSON", err)
 	}
 
-	return string(bytes), nil
+	return string(data), nil
 }
 
 // ToJSON converts any type to JSON string.
-func ToJSON(value interface{}) string {
-	result, _ := ToJSONE(value)
+func ToJSON(value interface{}, opts ...JSONOption) string {
+	result, _ := ToJSONE(value, opts...)
 	return result
 }
 
+// encodeJSON marshals value with the encoder settings of config.
+func encodeJSON(value interface{}, config jsonConfig) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(!config.noEscapeHTML)
+	encoder.SetIndent(config.prefix, config.indent)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	// Encode terminates the value with a newline, which json.Marshal does not.
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
 // FromJSONE converts JSON string to specified type with error.
-func FromJSONE(jsonStr string, target interface{}) error {
+// Invalid input is reported as a *JSONError wrapped in a conversion error.
+func FromJSONE(jsonStr string, target interface{}, opts ...JSONOption) error {
 	if jsonStr == "" {
 		return nil
 	}
 
-	// use json.Unmarshal to convert
-	err := json.Unmarshal([]byte(jsonStr), target)
-	if err != nil {
-		return internal.NewConversionError(jsonStr, "object", internal.ErrInvalidJSONFormat)
+	if err := decodeJSON([]byte(jsonStr), target, opts); err != nil {
+		return internal.NewConversionError(jsonStr, "object", err)
 	}
 
 	return nil
 }
 
 // FromJSON converts JSON string to specified type.
-func FromJSON(jsonStr string, target interface{}) {
-	_ = FromJSONE(jsonStr, target)
+func FromJSON(jsonStr string, target interface{}, opts ...JSONOption) {
+	_ = FromJSONE(jsonStr, target, opts...)
+}
+
+// FromJSONBytesE converts JSON bytes to specified type with error.
+func FromJSONBytesE(data []byte, target interface{}, opts ...JSONOption) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := decodeJSON(data, target, opts); err != nil {
+		return internal.NewConversionError(data, "object", err)
+	}
+
+	return nil
+}
+
+// FromJSONBytes converts JSON bytes to specified type.
+func FromJSONBytes(data []byte, target interface{}, opts ...JSONOption) {
+	_ = FromJSONBytesE(data, target, opts...)
+}
+
+// FromJSONReaderE reads JSON from r and converts it to specified type with error.
+func FromJSONReaderE(r io.Reader, target interface{}, opts ...JSONOption) error {
+	data, err := readJSON(r, "object")
+	if err != nil {
+		return err
+	}
+	return FromJSONBytesE(data, target, opts...)
+}
+
+// FromJSONReader reads JSON from r and converts it to specified type.
+func FromJSONReader(r io.Reader, target interface{}, opts ...JSONOption) {
+	_ = FromJSONReaderE(r, target, opts...)
 }
 
 // ToMapFromJSONE converts JSON string to map[string]interface{} with error.
-func ToMapFromJSONE(jsonStr string) (map[string]interface{}, error) {
+// Numbers are decoded as float64 unless JSONUseNumber is given.
+func ToMapFromJSONE(jsonStr string, opts ...JSONOption) (map[string]interface{}, error) {
 	if jsonStr == "" {
 		return nil, nil
 	}
@@ -56,23 +409,57 @@ func ToMapFromJSONE(jsonStr string) (map[string]interface{}, error) {
 	// create a map
 	result := make(map[string]interface{})
 
-	// use json.Unmarshal to convert
-	err := json.Unmarshal([]byte(jsonStr), &result)
-	if err != nil {
-		return nil, internal.NewConversionError(jsonStr, "map", internal.ErrInvalidJSONFormat)
+	if err := decodeJSON([]byte(jsonStr), &result, opts); err != nil {
+		return nil, internal.NewConversionError(jsonStr, "map", err)
 	}
 
 	return result, nil
 }
 
 // ToMapFromJSON converts JSON string to map[string]interface{}.
-func ToMapFromJSON(jsonStr string) map[string]interface{} {
-	result, _ := ToMapFromJSONE(jsonStr)
+func ToMapFromJSON(jsonStr string, opts ...JSONOption) map[string]interface{} {
+	result, _ := ToMapFromJSONE(jsonStr, opts...)
+	return result
+}
+
+// ToMapFromJSONBytesE converts JSON bytes to map[string]interface{} with error.
+func ToMapFromJSONBytesE(data []byte, opts ...JSONOption) (map[string]interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]interface{})
+	if err := decodeJSON(data, &result, opts); err != nil {
+		return nil, internal.NewConversionError(data, "map", err)
+	}
+
+	return result, nil
+}
+
+// ToMapFromJSONBytes converts JSON bytes to map[string]interface{}.
+func ToMapFromJSONBytes(data []byte, opts ...JSONOption) map[string]interface{} {
+	result, _ := ToMapFromJSONBytesE(data, opts...)
+	return result
+}
+
+// ToMapFromJSONReaderE reads JSON from r and converts it to map[string]interface{} with error.
+func ToMapFromJSONReaderE(r io.Reader, opts ...JSONOption) (map[string]interface{}, error) {
+	data, err := readJSON(r, "map")
+	if err != nil {
+		return nil, err
+	}
+	return ToMapFromJSONBytesE(data, opts...)
+}
+
+// ToMapFromJSONReader reads JSON from r and converts it to map[string]interface{}.
+func ToMapFromJSONReader(r io.Reader, opts ...JSONOption) map[string]interface{} {
+	result, _ := ToMapFromJSONReaderE(r, opts...)
 	return result
 }
 
 // ToSliceFromJSONE converts JSON string to []interface{} with error.
-func ToSliceFromJSONE(jsonStr string) ([]interface{}, error) {
+// Numbers are decoded as float64 unless JSONUseNumber is given.
+func ToSliceFromJSONE(jsonStr string, opts ...JSONOption) ([]interface{}, error) {
 	if jsonStr == "" {
 		return nil, nil
 	}
@@ -80,17 +467,50 @@ func ToSliceFromJSONE(jsonStr string) ([]interface{}, error) {
 	// create a slice
 	var result []interface{}
 
-	// use json.Unmarshal to convert
-	err := json.Unmarshal([]byte(jsonStr), &result)
-	if err != nil {
-		return nil, internal.NewConversionError(jsonStr, "slice", internal.ErrInvalidJSONFormat)
+	if err := decodeJSON([]byte(jsonStr), &result, opts); err != nil {
+		return nil, internal.NewConversionError(jsonStr, "slice", err)
 	}
 
 	return result, nil
 }
 
 // ToSliceFromJSON converts JSON string to []interface{}.
-func ToSliceFromJSON(jsonStr string) []interface{} {
-	result, _ := ToSliceFromJSONE(jsonStr)
+func ToSliceFromJSON(jsonStr string, opts ...JSONOption) []interface{} {
+	result, _ := ToSliceFromJSONE(jsonStr, opts...)
+	return result
+}
+
+// ToSliceFromJSONBytesE converts JSON bytes to []interface{} with error.
+func ToSliceFromJSONBytesE(data []byte, opts ...JSONOption) ([]interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var result []interface{}
+	if err := decodeJSON(data, &result, opts); err != nil {
+		return nil, internal.NewConversionError(data, "slice", err)
+	}
+
+	return result, nil
+}
+
+// ToSliceFromJSONBytes converts JSON bytes to []interface{}.
+func ToSliceFromJSONBytes(data []byte, opts ...JSONOption) []interface{} {
+	result, _ := ToSliceFromJSONBytesE(data, opts...)
+	return result
+}
+
+// ToSliceFromJSONReaderE reads JSON from r and converts it to []interface{} with error.
+func ToSliceFromJSONReaderE(r io.Reader, opts ...JSONOption) ([]interface{}, error) {
+	data, err := readJSON(r, "slice")
+	if err != nil {
+		return nil, err
+	}
+	return ToSliceFromJSONBytesE(data, opts...)
+}
+
+// ToSliceFromJSONReader reads JSON from r and converts it to []interface{}.
+func ToSliceFromJSONReader(r io.Reader, opts ...JSONOption) []interface{} {
+	result, _ := ToSliceFromJSONReaderE(r, opts...)
 	return result
 }
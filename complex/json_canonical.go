@@ -0,0 +1,302 @@
+package complex
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// canonicalJSON encodes value following RFC 8785, the JSON Canonicalization Scheme.
+// Structs are encoded with the same field names as ToStructE reads, and mconv tag
+// paths such as "meta.id" produce nested objects. All numbers are serialized as
+// IEEE 754 doubles, so integers beyond 2^53 lose precision, as the scheme requires.
+func canonicalJSON(value interface{}, config jsonConfig) ([]byte, error) {
+	n := &jsonNormalizer{config: config, visiting: make(map[jsonVisit]bool)}
+	tree, err := n.canonicalValue(reflect.ValueOf(value))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := writeCanonicalJSON(&buf, tree); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// canonicalValue converts rv into a tree of nil, bool, float64, string,
+// []interface{} and map[string]interface{} values.
+func (n *jsonNormalizer) canonicalValue(rv reflect.Value) (interface{}, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	if rv.Type() == jsonNumberType {
+		f, err := strconv.ParseFloat(rv.String(), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", rv.String())
+		}
+		return f, nil
+	}
+	if implementsJSONMarshaler(rv) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, nil
+		}
+		// Let the type encode itself, then canonicalize the result.
+		data, err := json.Marshal(rv.Interface())
+		if err != nil {
+			return nil, err
+		}
+		var generic interface{}
+		if err := decodeJSON(data, &generic, []JSONOption{JSONUseNumber()}); err != nil {
+			return nil, err
+		}
+		return n.canonicalValue(reflect.ValueOf(generic))
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			value, err := n.nonFiniteValue(f)
+			if err != nil {
+				return nil, err
+			}
+			return n.canonicalValue(reflect.ValueOf(value))
+		}
+		if rv.Kind() == reflect.Float32 {
+			// Use the shortest decimal form of the float32, as encoding/json does.
+			f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'g', -1, 32), 64)
+		}
+		return f, nil
+	case reflect.Complex64, reflect.Complex128:
+		return n.canonicalValue(reflect.ValueOf(n.complexValue(rv.Complex(), rv.Type().Bits())))
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return n.canonicalValue(rv.Elem())
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		leave, err := n.enter(rv, 0)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		return n.canonicalValue(rv.Elem())
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		leave, err := n.enter(rv, 0)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		result := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, err := jsonMapKey(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			value, err := n.canonicalValue(iter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			result[key] = value
+		}
+		return result, nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(rv.Bytes()), nil
+		}
+		leave, err := n.enter(rv, rv.Len())
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		return n.canonicalList(rv)
+	case reflect.Array:
+		return n.canonicalList(rv)
+	case reflect.Struct:
+		return n.canonicalStruct(rv)
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", rv.Type())
+}
+
+// canonicalList converts the elements of a slice or array.
+func (n *jsonNormalizer) canonicalList(rv reflect.Value) (interface{}, error) {
+	result := make([]interface{}, rv.Len())
+	for i := range result {
+		value, err := n.canonicalValue(rv.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		result[i] = value
+	}
+	return result, nil
+}
+
+// canonicalStruct converts a struct into an object keyed like ToStructE.
+func (n *jsonNormalizer) canonicalStruct(rv reflect.Value) (interface{}, error) {
+	decoder, err := getDecoder(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{}, len(decoder.FieldArr))
+	for _, fieldDecoder := range decoder.FieldArr {
+		value, err := n.canonicalValue(rv.FieldByIndex(fieldDecoder.Index))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldDecoder.Field.Name, err)
+		}
+		if fieldDecoder.Path == nil {
+			result[fieldDecoder.Name] = value
+			continue
+		}
+		if _, err := setPath(reflect.ValueOf(result), fieldDecoder.Path, value); err != nil {
+			return nil, fmt.Errorf("field %s: %w", fieldDecoder.Field.Name, err)
+		}
+	}
+	return result, nil
+}
+
+// writeCanonicalJSON writes a tree built by canonicalValue.
+func writeCanonicalJSON(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case float64:
+		buf.WriteString(formatCanonicalNumber(v))
+	case string:
+		return writeCanonicalString(buf, v)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, element); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalString(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeCanonicalJSON(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		// canonicalValue only produces the types above.
+		return fmt.Errorf("unsupported type %T", value)
+	}
+	return nil
+}
+
+// formatCanonicalNumber serializes f like ECMAScript's Number.prototype.toString.
+func formatCanonicalNumber(f float64) string {
+	if f == 0 {
+		// Also covers negative zero.
+		return "0"
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+	s := strconv.FormatFloat(f, format, -1, 64)
+	if format == 'e' {
+		// ECMAScript writes 1e-7 where Go writes 1e-07.
+		if n := len(s); n >= 4 && s[n-4] == 'e' && s[n-3] == '-' && s[n-2] == '0' {
+			s = s[:n-2] + s[n-1:]
+		}
+	}
+	return s
+}
+
+// writeCanonicalString writes s as a JSON string, escaping only quotes,
+// backslashes and control characters. Invalid UTF-8 is reported as an error.
+func writeCanonicalString(buf *bytes.Buffer, s string) error {
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("invalid UTF-8 in string %q", s)
+	}
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, c)
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+	buf.WriteByte('"')
+	return nil
+}
+
+// lessUTF16 compares a and b by their UTF-16 code units, as RFC 8785 sorts keys.
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
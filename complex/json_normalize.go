@@ -0,0 +1,361 @@
+package complex
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/graingo/mconv/basic"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// jsonNormalizer rewrites values that encoding/json cannot encode.
+type jsonNormalizer struct {
+	config jsonConfig
+	// visiting holds the maps, pointers and slices on the current path, to detect cycles.
+	visiting map[jsonVisit]bool
+}
+
+// jsonVisit identifies a reference value; the length distinguishes slices sharing an array.
+type jsonVisit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// normalizeJSON returns value with every part that encoding/json rejects replaced by
+// an encodable equivalent. Parts that need no change, including values implementing
+// json.Marshaler or encoding.TextMarshaler, are returned as they are.
+func normalizeJSON(value interface{}, config jsonConfig) (interface{}, error) {
+	n := &jsonNormalizer{config: config, visiting: make(map[jsonVisit]bool)}
+	result, _, err := n.normalize(reflect.ValueOf(value))
+	return result, err
+}
+
+// normalize returns the normalized form of rv and whether it differs from rv.
+func (n *jsonNormalizer) normalize(rv reflect.Value) (interface{}, bool, error) {
+	if !rv.IsValid() {
+		return nil, false, nil
+	}
+	if implementsJSONMarshaler(rv) {
+		return rv.Interface(), false, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Complex64, reflect.Complex128:
+		return n.complexValue(rv.Complex(), rv.Type().Bits()), true, nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return rv.Interface(), false, nil
+		}
+		value, err := n.nonFiniteValue(f)
+		return value, true, err
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return nil, true, nil
+	case reflect.Interface:
+		if rv.IsNil() {
+			return nil, false, nil
+		}
+		value, changed, err := n.normalize(rv.Elem())
+		if !changed {
+			return rv.Interface(), false, err
+		}
+		return value, true, err
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return rv.Interface(), false, nil
+		}
+		leave, err := n.enter(rv, 0)
+		if err != nil {
+			return nil, false, err
+		}
+		defer leave()
+		value, changed, err := n.normalize(rv.Elem())
+		if !changed {
+			return rv.Interface(), false, err
+		}
+		return value, true, err
+	case reflect.Map:
+		return n.mapValue(rv)
+	case reflect.Slice:
+		if rv.IsNil() || rv.Type().Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings.
+			return rv.Interface(), false, nil
+		}
+		leave, err := n.enter(rv, rv.Len())
+		if err != nil {
+			return nil, false, err
+		}
+		defer leave()
+		return n.listValue(rv)
+	case reflect.Array:
+		return n.listValue(rv)
+	case reflect.Struct:
+		return n.structValue(rv)
+	}
+	return rv.Interface(), false, nil
+}
+
+// enter marks a reference value as being visited and returns a func that unmarks it.
+func (n *jsonNormalizer) enter(rv reflect.Value, length int) (func(), error) {
+	visit := jsonVisit{ptr: rv.Pointer(), typ: rv.Type(), len: length}
+	if n.visiting[visit] {
+		return nil, fmt.Errorf("encountered a cycle via %s", rv.Type())
+	}
+	n.visiting[visit] = true
+	return func() { delete(n.visiting, visit) }, nil
+}
+
+// complexValue encodes c according to the complex encoding.
+func (n *jsonNormalizer) complexValue(c complex128, bits int) interface{} {
+	re, im := real(c), imag(c)
+	switch n.config.complexes {
+	case ComplexAsArray:
+		return []interface{}{n.floatPart(re), n.floatPart(im)}
+	case ComplexAsObject:
+		return map[string]interface{}{"real": n.floatPart(re), "imag": n.floatPart(im)}
+	}
+	return strconv.FormatComplex(c, 'g', -1, bits)
+}
+
+// floatPart returns a part of a complex number, encoding non-finite values as null
+// unless they are requested as strings; complex numbers are never rejected.
+func (n *jsonNormalizer) floatPart(f float64) interface{} {
+	if !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return f
+	}
+	if n.config.nonFinite == NonFiniteAsString {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return nil
+}
+
+// nonFiniteValue encodes NaN or an infinity according to the non-finite encoding.
+func (n *jsonNormalizer) nonFiniteValue(f float64) (interface{}, error) {
+	switch n.config.nonFinite {
+	case NonFiniteAsString:
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	case NonFiniteAsError:
+		return nil, fmt.Errorf("unsupported float value %v", f)
+	}
+	return nil, nil
+}
+
+// mapValue normalizes the values of a map and converts its keys to strings
+// when encoding/json does not support the key type.
+func (n *jsonNormalizer) mapValue(rv reflect.Value) (interface{}, bool, error) {
+	if rv.IsNil() {
+		return rv.Interface(), false, nil
+	}
+	leave, err := n.enter(rv, 0)
+	if err != nil {
+		return nil, false, err
+	}
+	defer leave()
+
+	changed := !isJSONKeyType(rv.Type().Key())
+	result := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key, err := jsonMapKey(iter.Key())
+		if err != nil {
+			return nil, false, err
+		}
+		value, valueChanged, err := n.normalize(iter.Value())
+		if err != nil {
+			return nil, false, fmt.Errorf("key %q: %w", key, err)
+		}
+		changed = changed || valueChanged
+		result[key] = value
+	}
+	if !changed {
+		return rv.Interface(), false, nil
+	}
+	return result, true, nil
+}
+
+// listValue normalizes the elements of a slice or array.
+func (n *jsonNormalizer) listValue(rv reflect.Value) (interface{}, bool, error) {
+	var result []interface{}
+	for i := 0; i < rv.Len(); i++ {
+		value, changed, err := n.normalize(rv.Index(i))
+		if err != nil {
+			return nil, false, fmt.Errorf("element %d: %w", i, err)
+		}
+		if changed && result == nil {
+			// Copy the elements seen so far on the first change.
+			result = make([]interface{}, i, rv.Len())
+			for j := 0; j < i; j++ {
+				result[j] = rv.Index(j).Interface()
+			}
+		}
+		if result != nil {
+			result = append(result, value)
+		}
+	}
+	if result == nil {
+		return rv.Interface(), false, nil
+	}
+	return result, true, nil
+}
+
+// structValue normalizes the exported fields of a struct. A struct with a field
+// that needs normalizing is replaced by a map keyed by its JSON field names.
+func (n *jsonNormalizer) structValue(rv reflect.Value) (interface{}, bool, error) {
+	result := make(map[string]interface{})
+	changed, err := n.collectFields(rv, result)
+	if err != nil || !changed {
+		return rv.Interface(), false, err
+	}
+	return result, true, nil
+}
+
+// collectFields stores the normalized fields of rv in result, inlining embedded
+// structs without a JSON name. Fields already in result take precedence, so outer
+// fields shadow embedded ones.
+func (n *jsonNormalizer) collectFields(rv reflect.Value, result map[string]interface{}) (bool, error) {
+	changed := false
+	var embedded []reflect.Value
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := parseJSONTag(field)
+		if skip {
+			continue
+		}
+		fv := rv.Field(i)
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if fv.Kind() == reflect.Ptr {
+					// Like encoding/json, ignore pointers to unexported struct types.
+					if !field.IsExported() {
+						continue
+					}
+					if fv.IsNil() {
+						continue
+					}
+					fv = fv.Elem()
+				}
+				if !implementsJSONMarshaler(fv) {
+					embedded = append(embedded, fv)
+					continue
+				}
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if omitEmpty && isEmptyJSONValue(fv) {
+			continue
+		}
+		value, fieldChanged, err := n.normalize(fv)
+		if err != nil {
+			return false, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		changed = changed || fieldChanged
+		if _, exists := result[name]; !exists {
+			result[name] = value
+		}
+	}
+	for _, fv := range embedded {
+		fieldsChanged, err := n.collectFields(fv, result)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || fieldsChanged
+	}
+	return changed, nil
+}
+
+// parseJSONTag returns the JSON name and omitempty flag of a field, and whether it is skipped.
+func parseJSONTag(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, false
+}
+
+// isEmptyJSONValue reports whether encoding/json treats v as empty for omitempty.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return v.IsZero()
+	}
+	return false
+}
+
+// implementsJSONMarshaler reports whether rv encodes itself through
+// json.Marshaler or encoding.TextMarshaler.
+func implementsJSONMarshaler(rv reflect.Value) bool {
+	t := rv.Type()
+	if t.Kind() == reflect.Interface {
+		return false
+	}
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+	if rv.CanAddr() {
+		pt := reflect.PtrTo(t)
+		return pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType)
+	}
+	return false
+}
+
+// isJSONKeyType reports whether encoding/json supports t as a map key type.
+func isJSONKeyType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return t.Implements(textMarshalerType)
+}
+
+// jsonMapKey converts a map key to the string encoding/json would use,
+// falling back to ToStringE for key types it does not support.
+func jsonMapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.Interface {
+		if key.IsNil() {
+			return "", fmt.Errorf("unsupported map key %v", nil)
+		}
+		key = key.Elem()
+	}
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if tm, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	return basic.ToStringE(key.Interface())
+}
@@ -0,0 +1,267 @@
+package complex
+
+import (
+	"errors"
+	"math/big"
+)
+
+// relaxedJSON translates relaxed JSON input into strict JSON for encoding/json.
+type relaxedJSON struct {
+	data []byte
+	pos  int
+	out  []byte
+	// offsets holds, for every byte of out, the offset of the input byte it came from,
+	// so that errors found in out can be reported at their position in data.
+	offsets []int64
+}
+
+// relaxJSON converts data written in the relaxed syntax accepted by JSONRelaxed into
+// strict JSON, returning the offset in data of every byte of the result.
+func relaxJSON(data []byte) ([]byte, []int64, error) {
+	r := &relaxedJSON{
+		data:    data,
+		out:     make([]byte, 0, len(data)),
+		offsets: make([]int64, 0, len(data)),
+	}
+	for r.pos < len(r.data) {
+		if err := r.next(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return r.out, r.offsets, nil
+}
+
+// next translates the token at the current position.
+func (r *relaxedJSON) next() error {
+	c := r.data[r.pos]
+	switch {
+	case c == '/':
+		return r.skipComment()
+	case c == '"':
+		return r.doubleQuoted()
+	case c == '\'':
+		return r.singleQuoted()
+	case c == ',':
+		// Drop trailing commas, which follow a value and precede a closing bracket.
+		next := r.significant(r.pos + 1)
+		if r.afterValue() && next < len(r.data) && (r.data[next] == '}' || r.data[next] == ']') {
+			r.pos++
+			return nil
+		}
+	case c == '+' || c == '-' || (c >= '0' && c <= '9'):
+		return r.number()
+	case isIdentStart(c):
+		return r.identifier()
+	}
+	r.emit(r.pos, c)
+	r.pos++
+	return nil
+}
+
+// afterValue reports whether the last significant byte written ends a value.
+func (r *relaxedJSON) afterValue() bool {
+	for i := len(r.out) - 1; i >= 0; i-- {
+		switch r.out[i] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ':', ',', '[', '{':
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// emit appends bytes that come from the input byte at offset src.
+func (r *relaxedJSON) emit(src int, bytes ...byte) {
+	for _, b := range bytes {
+		r.out = append(r.out, b)
+		r.offsets = append(r.offsets, int64(src))
+	}
+}
+
+// errorAt reports a syntax error at input offset pos.
+func (r *relaxedJSON) errorAt(pos int, msg string) error {
+	return newJSONError(r.data, errors.New(msg), int64(pos)+1)
+}
+
+// skipComment skips a // or /* */ comment at the current position.
+func (r *relaxedJSON) skipComment() error {
+	end, ok := r.commentEnd(r.pos)
+	if !ok {
+		return r.errorAt(r.pos, "invalid character '/' looking for beginning of value")
+	}
+	if end < 0 {
+		return r.errorAt(r.pos, "unterminated comment")
+	}
+	r.pos = end
+	return nil
+}
+
+// commentEnd returns the offset after the comment starting at pos, or -1 for an
+// unterminated block comment. ok is false if no comment starts at pos.
+func (r *relaxedJSON) commentEnd(pos int) (end int, ok bool) {
+	if pos+1 >= len(r.data) || r.data[pos] != '/' {
+		return 0, false
+	}
+	switch r.data[pos+1] {
+	case '/':
+		for end = pos + 2; end < len(r.data) && r.data[end] != '\n'; end++ {
+		}
+		return end, true
+	case '*':
+		for end = pos + 2; end+1 < len(r.data); end++ {
+			if r.data[end] == '*' && r.data[end+1] == '/' {
+				return end + 2, true
+			}
+		}
+		return -1, true
+	}
+	return 0, false
+}
+
+// significant returns the offset of the first byte at or after pos that is
+// neither whitespace nor part of a comment.
+func (r *relaxedJSON) significant(pos int) int {
+	for pos < len(r.data) {
+		switch r.data[pos] {
+		case ' ', '\t', '\n', '\r':
+			pos++
+			continue
+		case '/':
+			if end, ok := r.commentEnd(pos); ok && end >= 0 {
+				pos = end
+				continue
+			}
+		}
+		break
+	}
+	return pos
+}
+
+// doubleQuoted copies a double-quoted string unchanged.
+func (r *relaxedJSON) doubleQuoted() error {
+	start := r.pos
+	r.emit(r.pos, '"')
+	for r.pos++; r.pos < len(r.data); r.pos++ {
+		c := r.data[r.pos]
+		r.emit(r.pos, c)
+		switch c {
+		case '\\':
+			if r.pos+1 < len(r.data) {
+				r.pos++
+				r.emit(r.pos, r.data[r.pos])
+			}
+		case '"':
+			r.pos++
+			return nil
+		}
+	}
+	return r.errorAt(start, "unterminated string")
+}
+
+// singleQuoted converts a single-quoted string into a double-quoted one.
+func (r *relaxedJSON) singleQuoted() error {
+	start := r.pos
+	r.emit(r.pos, '"')
+	for r.pos++; r.pos < len(r.data); r.pos++ {
+		c := r.data[r.pos]
+		switch c {
+		case '\\':
+			if r.pos+1 < len(r.data) && r.data[r.pos+1] == '\'' {
+				// \' needs no escape inside double quotes.
+				r.pos++
+				r.emit(r.pos, '\'')
+				continue
+			}
+			r.emit(r.pos, c)
+			if r.pos+1 < len(r.data) {
+				r.pos++
+				r.emit(r.pos, r.data[r.pos])
+			}
+		case '"':
+			r.emit(r.pos, '\\', '"')
+		case '\'':
+			r.emit(r.pos, '"')
+			r.pos++
+			return nil
+		default:
+			r.emit(r.pos, c)
+		}
+	}
+	return r.errorAt(start, "unterminated string")
+}
+
+// number copies a number, dropping a leading plus sign and converting
+// hexadecimal numbers such as 0x1F to decimal.
+func (r *relaxedJSON) number() error {
+	start := r.pos
+	sign := r.data[r.pos]
+	if sign == '+' || sign == '-' {
+		r.pos++
+	}
+	if r.pos+1 < len(r.data) && r.data[r.pos] == '0' && (r.data[r.pos+1] == 'x' || r.data[r.pos+1] == 'X') {
+		digits := r.pos + 2
+		end := digits
+		for end < len(r.data) && isHexDigit(r.data[end]) {
+			end++
+		}
+		n, ok := new(big.Int).SetString(string(r.data[digits:end]), 16)
+		if !ok {
+			return r.errorAt(start, "invalid hexadecimal number")
+		}
+		if sign == '-' {
+			r.emit(start, '-')
+		}
+		r.emit(start, []byte(n.String())...)
+		r.pos = end
+		return nil
+	}
+	if sign == '-' {
+		r.emit(start, '-')
+	}
+	for r.pos < len(r.data) && isNumberByte(r.data[r.pos]) {
+		r.emit(r.pos, r.data[r.pos])
+		r.pos++
+	}
+	return nil
+}
+
+// identifier copies true, false and null, and quotes unquoted object keys.
+func (r *relaxedJSON) identifier() error {
+	start := r.pos
+	for r.pos < len(r.data) && (isIdentStart(r.data[r.pos]) || (r.data[r.pos] >= '0' && r.data[r.pos] <= '9')) {
+		r.pos++
+	}
+	word := r.data[start:r.pos]
+	switch string(word) {
+	case "true", "false", "null":
+		for i, b := range word {
+			r.emit(start+i, b)
+		}
+		return nil
+	}
+	if next := r.significant(r.pos); next >= len(r.data) || r.data[next] != ':' {
+		return r.errorAt(start, "invalid character '"+string(word[0])+"' looking for beginning of value")
+	}
+	r.emit(start, '"')
+	for i, b := range word {
+		r.emit(start+i, b)
+	}
+	r.emit(r.pos-1, '"')
+	return nil
+}
+
+// isIdentStart reports whether c may start an unquoted key. Bytes of multi-byte
+// UTF-8 sequences are accepted so that keys may contain non-ASCII letters.
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isNumberByte(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '.' || c == 'e' || c == 'E' || c == '+' || c == '-'
+}
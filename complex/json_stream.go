@@ -0,0 +1,248 @@
+package complex
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// StreamJSONE decodes a top-level JSON array, or a sequence of newline-delimited JSON
+// values (NDJSON), from r one element at a time and calls fn for each of them.
+// Only one element is held in memory at a time, so arbitrarily large inputs can be processed.
+//
+// Each element is converted into T like a ToStructE field, so T may be a struct decoded
+// through its mconv/json tags, a map, a slice or a basic type. When an element cannot be
+// converted, fn is called with the zero T and the error, and may return nil to skip it.
+// A non-nil error returned by fn stops the stream and is returned as-is.
+//
+// Input starting with '[' is treated as an array unless JSONLines is given. Otherwise
+// each non-blank line holds one value, and a malformed line is reported to fn like a
+// conversion error so that it can be skipped. Malformed JSON in an array cannot be
+// recovered from and is returned as a *JSONError.
+func StreamJSONE[T any](r io.Reader, fn func(index int, item T, err error) error, opts ...JSONOption) error {
+	if r == nil {
+		return nil
+	}
+	config := newJSONConfig(opts)
+
+	br := bufio.NewReader(r)
+	first, err := peekJSONStart(br)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return internal.NewConversionError(r, "stream", err)
+	}
+
+	if first != '[' || config.lines {
+		return streamJSONLines(br, fn, config)
+	}
+
+	decoder := json.NewDecoder(br)
+	if config.useNumber {
+		decoder.UseNumber()
+	}
+	// Consume the opening bracket.
+	if _, err := decoder.Token(); err != nil {
+		return newStreamJSONError(decoder, err)
+	}
+
+	for index := 0; decoder.More(); index++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return newStreamJSONError(decoder, err)
+		}
+
+		item, err := decodeStreamItem[T](raw, config)
+		if err != nil {
+			err = fmt.Errorf("record %d: %w", index, err)
+		}
+		if err := fn(index, item, err); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing bracket and reject trailing data.
+	if _, err := decoder.Token(); err != nil {
+		return newStreamJSONError(decoder, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("invalid character after top-level value")
+		}
+		return newStreamJSONError(decoder, err)
+	}
+	return nil
+}
+
+// streamJSONLines decodes one value per non-blank line of br. Blank lines are skipped
+// and do not count as records.
+func streamJSONLines[T any](br *bufio.Reader, fn func(index int, item T, err error) error, config jsonConfig) error {
+	index := 0
+	for lineNo := 1; ; lineNo++ {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return internal.NewConversionError(nil, "stream", readErr)
+		}
+		if raw := bytes.TrimSpace(line); len(raw) > 0 {
+			item, err := decodeStreamItem[T](raw, config)
+			if err != nil {
+				err = fmt.Errorf("record %d at line %d: %w", index, lineNo, err)
+			}
+			if err := fn(index, item, err); err != nil {
+				return err
+			}
+			index++
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// peekJSONStart returns the first non-whitespace byte of br without consuming it.
+func peekJSONStart(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(rune(b[0])) {
+			return b[0], nil
+		}
+		if _, err := br.ReadByte(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// decodeStreamItem decodes a single raw element into T. Elements are decoded with
+// encoding/json directly, so that json.Unmarshaler implementations run and integers
+// keep their precision. Targets with mconv tags, and elements that encoding/json
+// cannot store in T, such as "2" for an int field, are converted like ToStructE
+// fields instead, unless T implements json.Unmarshaler itself.
+func decodeStreamItem[T any](raw json.RawMessage, config jsonConfig) (T, error) {
+	var item T
+	itemRv := reflect.ValueOf(&item).Elem()
+	if !hasMconvTags(itemRv.Type()) {
+		err := decodeJSON(raw, &item, streamItemOptions(config))
+		_, isUnmarshaler := interface{}(&item).(json.Unmarshaler)
+		var syntaxErr *json.SyntaxError
+		if err == nil || isUnmarshaler || errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return item, err
+		}
+		itemRv.Set(reflect.Zero(itemRv.Type()))
+	}
+
+	var value interface{}
+	if err := decodeJSON(raw, &value, []JSONOption{JSONUseNumber()}); err != nil {
+		return item, err
+	}
+	if !config.useNumber {
+		value = smallNumbersToFloat(value)
+	}
+	if value == nil {
+		return item, nil
+	}
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+	if err := setFieldValue(itemRv, value, defaultHooks()...); err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+// smallNumbersToFloat replaces the json.Number values in decoded JSON with float64,
+// except for integers beyond ±2^53 that float64 cannot represent exactly.
+func smallNumbersToFloat(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			if i > 1<<53 || i < -(1<<53) {
+				return v
+			}
+			return float64(i)
+		}
+		if !strings.ContainsAny(string(v), ".eE") {
+			// An integer beyond int64.
+			return v
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = smallNumbersToFloat(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = smallNumbersToFloat(e)
+		}
+	}
+	return value
+}
+
+// mconvTagCache caches hasMconvTags results by type.
+var mconvTagCache sync.Map
+
+// hasMconvTags reports whether t, or a type it contains, has struct fields with
+// mconv tags, which encoding/json would ignore.
+func hasMconvTags(t reflect.Type) bool {
+	if cached, ok := mconvTagCache.Load(t); ok {
+		return cached.(bool)
+	}
+	result := findMconvTags(t, make(map[reflect.Type]bool))
+	mconvTagCache.Store(t, result)
+	return result
+}
+
+// findMconvTags implements hasMconvTags, skipping types already visited.
+func findMconvTags(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return findMconvTags(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if _, ok := field.Tag.Lookup("mconv"); ok || findMconvTags(field.Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// streamItemOptions returns the options used to decode a single stream element.
+func streamItemOptions(config jsonConfig) []JSONOption {
+	if config.useNumber {
+		return []JSONOption{JSONUseNumber()}
+	}
+	return nil
+}
+
+// newStreamJSONError wraps a decoding error with the stream offset.
+// Line and column are unknown because the input is not kept.
+func newStreamJSONError(decoder *json.Decoder, err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	jsonErr := &JSONError{Err: err, Offset: decoder.InputOffset()}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		jsonErr.Offset = syntaxErr.Offset
+	}
+	return internal.NewConversionError(nil, "stream", jsonErr)
+}
@@ -0,0 +1,40 @@
+//go:build go1.23
+
+package complex
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+// errStopStream is returned from the StreamJSONE callback when the consumer of
+// StreamJSONSeq stops iterating.
+var errStopStream = errors.New("stream stopped")
+
+// StreamJSONSeq returns an iterator over the elements decoded by StreamJSONE.
+// Elements that cannot be converted into T are yielded with the zero T and their error,
+// and iteration continues, as are malformed NDJSON lines; a malformed array or a read
+// error is yielded last.
+//
+//	for user, err := range StreamJSONSeq[User](file) {
+//		if err != nil {
+//			log.Print(err)
+//			continue
+//		}
+//		...
+//	}
+func StreamJSONSeq[T any](r io.Reader, opts ...JSONOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		err := StreamJSONE(r, func(_ int, item T, err error) error {
+			if !yield(item, err) {
+				return errStopStream
+			}
+			return nil
+		}, opts...)
+		if err != nil && err != errStopStream {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
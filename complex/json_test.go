@@ -1,10 +1,17 @@
 package complex_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/graingo/mconv"
+	"github.com/graingo/mconv/internal"
 )
 
 type TestPerson struct {
@@ -98,12 +105,122 @@ func TestToJSONE(t *testing.T) {
 		t.Errorf("mconv.ToJSONE() = %v; want %v", json, expected)
 	}
 
+	// Channels cannot be represented in JSON and are encoded as null
+	json, err = mconv.ToJSONE(make(chan int))
+	if err != nil || json != "null" {
+		t.Errorf("mconv.ToJSONE(chan) = %v, %v; want null", json, err)
+	}
+
 	// Test error cases
-	// Create a value that cannot be serialized to JSON
-	badValue := make(chan int)
-	_, err = mconv.ToJSONE(badValue)
+	_, err = mconv.ToJSONE(math.NaN(), mconv.JSONNonFiniteAs(mconv.NonFiniteAsError))
 	if err == nil {
-		t.Errorf("mconv.ToJSONE() with bad value expected error")
+		t.Errorf("mconv.ToJSONE() with NaN and NonFiniteAsError expected error")
+	}
+	cyclic := map[string]interface{}{"c": complex(1, 2)}
+	cyclic["self"] = cyclic
+	if _, err = mconv.ToJSONE(cyclic); err == nil {
+		t.Errorf("mconv.ToJSONE() with cyclic value expected error")
+	}
+}
+
+type jsonNormalizeEmbedded struct {
+	Shared string `json:"shared"`
+	Inner  int    `json:"inner,omitempty"`
+}
+
+type jsonNormalizeRecord struct {
+	jsonNormalizeEmbedded
+	Shared  string                      `json:"shared"`
+	Meta    map[interface{}]interface{} `json:"meta"`
+	Skipped string                      `json:"-"`
+	Hidden  string                      `json:"hidden,omitempty"`
+	When    time.Time                   `json:"when"`
+}
+
+func TestToJSONENormalize(t *testing.T) {
+	// YAML-style maps have their keys converted to strings, recursively
+	yamlDoc := map[interface{}]interface{}{
+		"name":  "app",
+		1:       "one",
+		true:    []interface{}{map[interface{}]interface{}{"port": 8080}},
+		"ratio": 0.5,
+	}
+	result, err := mconv.ToJSONE(yamlDoc)
+	expected := `{"1":"one","name":"app","ratio":0.5,"true":[{"port":8080}]}`
+	if err != nil || result != expected {
+		t.Errorf("ToJSONE(yaml map) = %v, %v; want %v", result, err, expected)
+	}
+
+	// Structs holding unsupported values keep their JSON field names
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := jsonNormalizeRecord{
+		jsonNormalizeEmbedded: jsonNormalizeEmbedded{Shared: "inner", Inner: 7},
+		Shared:                "outer",
+		Meta:                  map[interface{}]interface{}{"k": "v", 1.5: "x"},
+		Skipped:               "x",
+		When:                  when,
+	}
+	result, err = mconv.ToJSONE(&record)
+	expected = `{"inner":7,"meta":{"1.5":"x","k":"v"},"shared":"outer","when":"2024-01-02T03:04:05Z"}`
+	if err != nil || result != expected {
+		t.Errorf("ToJSONE(struct) = %v, %v; want %v", result, err, expected)
+	}
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		opts     []mconv.JSONOption
+		expected string
+	}{
+		{"complex default", complex(1, 2), nil, `"(1+2i)"`},
+		{"complex64", complex64(complex(1.5, -1)), nil, `"(1.5-1i)"`},
+		{"complex array", []complex128{complex(1, 2)}, []mconv.JSONOption{mconv.JSONComplexAs(mconv.ComplexAsArray)}, `[[1,2]]`},
+		{"complex object", complex(0, 1), []mconv.JSONOption{mconv.JSONComplexAs(mconv.ComplexAsObject)}, `{"imag":1,"real":0}`},
+		{"NaN default", []float64{1, math.NaN()}, nil, `[1,null]`},
+		{"Inf string", map[string]float64{"max": math.Inf(1), "min": math.Inf(-1)}, []mconv.JSONOption{mconv.JSONNonFiniteAs(mconv.NonFiniteAsString)}, `{"max":"+Inf","min":"-Inf"}`},
+		{"NaN float32 string", float32(math.NaN()), []mconv.JSONOption{mconv.JSONNonFiniteAs(mconv.NonFiniteAsString)}, `"NaN"`},
+		{"func in map", map[string]interface{}{"f": func() {}, "a": 1}, nil, `{"a":1,"f":null}`},
+		{"bytes untouched", map[interface{}]interface{}{"b": []byte("hi")}, nil, `{"b":"aGk="}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := mconv.ToJSONE(tt.value, tt.opts...)
+			if err != nil || result != tt.expected {
+				t.Errorf("ToJSONE() = %v, %v; want %v", result, err, tt.expected)
+			}
+		})
+	}
+
+	// Normalized values round-trip through the converters
+	c := mconv.ToComplex128(strings.Trim(mconv.ToJSON(complex(3, 4)), `"`))
+	if c != complex(3, 4) {
+		t.Errorf("complex round trip = %v", c)
+	}
+}
+
+func TestToJSONEFormatting(t *testing.T) {
+	type item struct {
+		Zeta  string `json:"zeta"`
+		Alpha int64  `json:"alpha"`
+	}
+	value := map[string]interface{}{"b": item{Zeta: "<a&b>", Alpha: 1234567890123456789}, "a": 1}
+
+	result, err := mconv.ToJSONE(value)
+	expected := `{"a":1,"b":{"zeta":"\u003ca\u0026b\u003e","alpha":1234567890123456789}}`
+	if err != nil || result != expected {
+		t.Errorf("ToJSONE() = %v, %v; want %v", result, err, expected)
+	}
+
+	result, err = mconv.ToJSONE(value, mconv.JSONSortKeys(), mconv.JSONDisableHTMLEscape())
+	expected = `{"a":1,"b":{"alpha":1234567890123456789,"zeta":"<a&b>"}}`
+	if err != nil || result != expected {
+		t.Errorf("ToJSONE(sorted) = %v, %v; want %v", result, err, expected)
+	}
+
+	result, err = mconv.ToJSONE(item{Zeta: "z"}, mconv.JSONIndent("", "  "), mconv.JSONSortKeys())
+	expected = "{\n  \"alpha\": 0,\n  \"zeta\": \"z\"\n}"
+	if err != nil || result != expected {
+		t.Errorf("ToJSONE(indented) = %q, %v; want %q", result, err, expected)
 	}
 }
 
@@ -256,3 +373,260 @@ func TestToSliceFromJSONE(t *testing.T) {
 		t.Errorf("ToSliceFromJSONE()[2] = %v; want %v", result[2], "Bob")
 	}
 }
+
+func TestToMapFromJSONEUseNumber(t *testing.T) {
+	input := `{"id": 12345678901234567891, "ratio": 0.5, "list": [9007199254740993]}`
+
+	result, err := mconv.ToMapFromJSONE(input, mconv.JSONUseNumber())
+	if err != nil {
+		t.Fatalf("ToMapFromJSONE() error = %v", err)
+	}
+	if result["id"] != json.Number("12345678901234567891") {
+		t.Errorf("ToMapFromJSONE() id = %#v", result["id"])
+	}
+	if got := mconv.ToUint64(result["id"]); got != 12345678901234567891 {
+		t.Errorf("ToUint64(id) = %d", got)
+	}
+	if got := mconv.ToInt64(result["list"].([]interface{})[0]); got != 9007199254740993 {
+		t.Errorf("ToInt64(list[0]) = %d", got)
+	}
+
+	slice, err := mconv.ToSliceFromJSONE(`[1, 2.5]`, mconv.JSONUseNumber())
+	if err != nil || !reflect.DeepEqual(slice, []interface{}{json.Number("1"), json.Number("2.5")}) {
+		t.Errorf("ToSliceFromJSONE() = %#v, %v", slice, err)
+	}
+}
+
+func TestJSONErrorLocation(t *testing.T) {
+	input := "{\n  \"name\": \"John\",\n  \"age\": ?\n}"
+	_, err := mconv.ToMapFromJSONE(input)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var jsonErr *mconv.JSONError
+	if !errors.As(err, &jsonErr) {
+		t.Fatalf("expected *JSONError, got %T", err)
+	}
+	if jsonErr.Line != 3 || jsonErr.Column != 10 {
+		t.Errorf("position = %d:%d; want 3:10", jsonErr.Line, jsonErr.Column)
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("expected the original *json.SyntaxError in the chain")
+	}
+	if !errors.Is(err, internal.ErrInvalidJSONFormat) {
+		t.Errorf("expected error to match ErrInvalidJSONFormat")
+	}
+
+	var person TestPerson
+	err = mconv.FromJSONE(`{"name": "John", "age": "thirty"}`, &person)
+	if !errors.As(err, &jsonErr) || jsonErr.Field != "age" {
+		t.Fatalf("expected type error for field age, got %v", err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Errorf("expected the original *json.UnmarshalTypeError in the chain")
+	}
+	if !strings.Contains(err.Error(), "line 1, column") {
+		t.Errorf("error %q does not include the position", err)
+	}
+
+	if err := mconv.FromJSONE(`{"name": "John"} extra`, &person); err == nil {
+		t.Error("expected error for trailing data")
+	}
+	if err := mconv.FromJSONE(`{"nickname": "J"}`, &person, mconv.JSONDisallowUnknownFields()); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestJSONBytesAndReader(t *testing.T) {
+	data := []byte(`{"name":"John Doe","age":30}`)
+
+	var person TestPerson
+	if err := mconv.FromJSONBytesE(data, &person); err != nil || person.Name != "John Doe" {
+		t.Errorf("FromJSONBytesE() = %+v, %v", person, err)
+	}
+	person = TestPerson{}
+	if err := mconv.FromJSONReaderE(bytes.NewReader(data), &person); err != nil || person.Age != 30 {
+		t.Errorf("FromJSONReaderE() = %+v, %v", person, err)
+	}
+
+	m, err := mconv.ToMapFromJSONBytesE(data, mconv.JSONUseNumber())
+	if err != nil || m["age"] != json.Number("30") {
+		t.Errorf("ToMapFromJSONBytesE() = %v, %v", m, err)
+	}
+	m, err = mconv.ToMapFromJSONReaderE(strings.NewReader(string(data)))
+	if err != nil || m["age"] != float64(30) {
+		t.Errorf("ToMapFromJSONReaderE() = %v, %v", m, err)
+	}
+
+	s, err := mconv.ToSliceFromJSONBytesE([]byte(`["a"]`))
+	if err != nil || !reflect.DeepEqual(s, []interface{}{"a"}) {
+		t.Errorf("ToSliceFromJSONBytesE() = %v, %v", s, err)
+	}
+	s, err = mconv.ToSliceFromJSONReaderE(strings.NewReader(`["a", 1]`), mconv.JSONUseNumber())
+	if err != nil || !reflect.DeepEqual(s, []interface{}{"a", json.Number("1")}) {
+		t.Errorf("ToSliceFromJSONReaderE() = %v, %v", s, err)
+	}
+
+	if _, err := mconv.ToSliceFromJSONReaderE(strings.NewReader(`[1,`)); err == nil {
+		t.Error("expected error for truncated input")
+	}
+	if m, err := mconv.ToMapFromJSONBytesE(nil); m != nil || err != nil {
+		t.Errorf("ToMapFromJSONBytesE(nil) = %v, %v", m, err)
+	}
+}
+
+type canonicalPayload struct {
+	ID      int64                  `mconv:"id"`
+	Event   string                 `json:"event"`
+	OrderID string                 `mconv:"order.id"`
+	Total   float64                `mconv:"order.total"`
+	Sent    time.Time              `yaml:"sent"`
+	Extra   map[string]interface{} `json:"extra,omitempty"`
+	Ignored string                 `json:"-"`
+}
+
+func TestToJSONECanonical(t *testing.T) {
+	canonical := mconv.JSONCanonical()
+
+	// RFC 8785 sorts keys by UTF-16 code units, so U+1F600 sorts before U+FB33.
+	keys := map[string]interface{}{
+		"€": "Euro Sign", "\r": "Carriage Return", "דּ": "Hebrew Letter Dalet With Dagesh",
+		"1": "One", "\U0001F600": "Emoji: Grinning Face", "\u0080": "Control", "ö": "Latin Small Letter O With Diaeresis",
+	}
+	result, err := mconv.ToJSONE(keys, canonical)
+	expected := `{"\r":"Carriage Return","1":"One","` + "\u0080" + `":"Control","ö":"Latin Small Letter O With Diaeresis",` +
+		`"€":"Euro Sign","😀":"Emoji: Grinning Face","` + "דּ" + `":"Hebrew Letter Dalet With Dagesh"}`
+	if err != nil || result != expected {
+		t.Errorf("ToJSONE(keys) = %v, %v; want %v", result, err, expected)
+	}
+
+	// Only quotes, backslashes and control characters are escaped.
+	result, _ = mconv.ToJSONE("€$\u000f\nA'B\"\\\\\"/<&>", canonical)
+	expected = `"€$\u000f\nA'B\"\\\\\"/<&>"`
+	if result != expected {
+		t.Errorf("ToJSONE(string) = %v; want %v", result, expected)
+	}
+
+	numbers := []struct {
+		value    interface{}
+		expected string
+	}{
+		{math.Copysign(0, -1), "0"},
+		{1e21, "1e+21"},
+		{1e20, "100000000000000000000"},
+		{1e-7, "1e-7"},
+		{0.000001, "0.000001"},
+		{333333333.33333329, "333333333.3333333"},
+		{5e-324, "5e-324"},
+		{1.7976931348623157e308, "1.7976931348623157e+308"},
+		{-1.5, "-1.5"},
+		{int64(9007199254740992), "9007199254740992"},
+		{uint8(7), "7"},
+		{float32(0.1), "0.1"},
+		{json.Number("295147905179352830000"), "295147905179352830000"},
+	}
+	for _, tt := range numbers {
+		if result, err := mconv.ToJSONE(tt.value, canonical); err != nil || result != tt.expected {
+			t.Errorf("ToJSONE(%v) = %v, %v; want %v", tt.value, result, err, tt.expected)
+		}
+	}
+
+	// Structs use ToStructE field names, and tag paths become nested objects.
+	payload := canonicalPayload{
+		ID:      1,
+		Event:   "order.paid",
+		OrderID: "A-1",
+		Total:   12.5,
+		Sent:    time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+		Ignored: "x",
+	}
+	result, err = mconv.ToJSONE(&payload, canonical)
+	expected = `{"event":"order.paid","extra":null,"id":1,"order":{"id":"A-1","total":12.5},"sent":"2024-05-06T07:08:09Z"}`
+	if err != nil || result != expected {
+		t.Errorf("ToJSONE(struct) = %v, %v; want %v", result, err, expected)
+	}
+
+	// Equal values produce identical output regardless of their Go types.
+	a, _ := mconv.ToJSONE(map[interface{}]interface{}{"b": []int{1, 2}, "a": 1.0}, canonical)
+	b, _ := mconv.ToJSONE(mconv.ToMapFromJSON(`{"a": 1, "b": [1.0, 2e0]}`, mconv.JSONUseNumber()), canonical)
+	if a != b || a != `{"a":1,"b":[1,2]}` {
+		t.Errorf("canonical outputs differ: %v and %v", a, b)
+	}
+
+	// Canonical mode ignores indentation and rejects invalid UTF-8.
+	if result, _ := mconv.ToJSONE([]int{1}, canonical, mconv.JSONIndent("", "  ")); result != "[1]" {
+		t.Errorf("ToJSONE(indented) = %q; want [1]", result)
+	}
+	if _, err := mconv.ToJSONE("\xff", canonical); err == nil {
+		t.Error("expected error for invalid UTF-8")
+	}
+}
+
+func TestRelaxedJSON(t *testing.T) {
+	config := `
+	// Service configuration
+	{
+		name: 'api "v2"',        /* single quotes */
+		$port: 0x1F90,
+		'limits': {max: +10, min: -0x10, ratio: 1.5e+2,},
+		"hosts": ['a', 'it\'s', "b",
+		],
+		enabled: true, // trailing comment
+		note: "slash // inside /* string */",
+	}
+	`
+	m, err := mconv.ToMapFromJSONE(config, mconv.JSONRelaxed(), mconv.JSONUseNumber())
+	if err != nil {
+		t.Fatalf("ToMapFromJSONE(relaxed) unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"name":    `api "v2"`,
+		"$port":   json.Number("8080"),
+		"limits":  map[string]interface{}{"max": json.Number("10"), "min": json.Number("-16"), "ratio": json.Number("1.5e+2")},
+		"hosts":   []interface{}{"a", "it's", "b"},
+		"enabled": true,
+		"note":    "slash // inside /* string */",
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("ToMapFromJSONE(relaxed) = %v; want %v", m, expected)
+	}
+
+	// Strict mode still rejects relaxed syntax.
+	if _, err := mconv.ToMapFromJSONE(`{a: 1}`); !errors.Is(err, internal.ErrInvalidJSONFormat) {
+		t.Errorf("strict ToMapFromJSONE error = %v; want ErrInvalidJSONFormat", err)
+	}
+
+	var person TestPerson
+	if err := mconv.FromJSONE(`{name: 'Ann', age: 0x20, /* none */}`, &person, mconv.JSONRelaxed()); err != nil || person.Name != "Ann" || person.Age != 32 {
+		t.Errorf("FromJSONE(relaxed) = %+v, %v", person, err)
+	}
+	s, err := mconv.ToSliceFromJSONE("[1, 2, // two\n 3,]", mconv.JSONRelaxed())
+	if err != nil || !reflect.DeepEqual(s, []interface{}{1.0, 2.0, 3.0}) {
+		t.Errorf("ToSliceFromJSONE(relaxed) = %v, %v", s, err)
+	}
+
+	// Errors point at the original input.
+	errorTests := []struct {
+		input        string
+		line, column int
+	}{
+		{"{\n  // comment\n  a: 1,\n  b: ,\n}", 4, 6},
+		{"{\n  a: 'unterminated\n}", 2, 6},
+		{"/* open\n{}", 1, 1},
+		{"{\n  a: nope\n}", 2, 6},
+	}
+	for _, tt := range errorTests {
+		_, err := mconv.ToMapFromJSONE(tt.input, mconv.JSONRelaxed())
+		var jsonErr *mconv.JSONError
+		if !errors.As(err, &jsonErr) {
+			t.Errorf("ToMapFromJSONE(%q) error = %v; want *JSONError", tt.input, err)
+			continue
+		}
+		if jsonErr.Line != tt.line || jsonErr.Column != tt.column {
+			t.Errorf("ToMapFromJSONE(%q) error at %d:%d; want %d:%d (%v)", tt.input, jsonErr.Line, jsonErr.Column, tt.line, tt.column, err)
+		}
+	}
+}
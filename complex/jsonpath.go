@@ -0,0 +1,694 @@
+package complex
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/graingo/mconv/basic"
+	"github.com/graingo/mconv/internal"
+)
+
+// Query evaluates a JSONPath expression against data and returns the matched
+// values in document order, with map values ordered by key. Invalid expressions
+// yield no results. The results can be passed directly to the typed converters,
+// as in ToIntE(Query(data, "$.users[0].age")[0]). See QueryE for the supported syntax.
+func Query(data interface{}, expr string) []*Var {
+	result, _ := QueryE(data, expr)
+	return result
+}
+
+// QueryE evaluates a JSONPath expression against data with error. The supported
+// subset covers:
+//
+//	$                   the root value
+//	.name, ['name']     a map key or struct field
+//	[0], [-1]           a list element, counting from the end when negative
+//	[0:2], [::2]        a list slice with optional start, end and step
+//	.*, [*]             every element or map value
+//	..name, ..[*]       recursive descent
+//	['a','b'], [0,1]    a union of names or indices
+//	[?(@.age > 30)]     a filter over elements or map values
+//
+// Filters compare @ (the current value) or $ paths with ==, !=, <, <=, > and >=,
+// against numbers, 'strings', true, false and null, and combine them with &&, ||,
+// ! and parentheses. A path on its own, such as [?(@.email)], tests for existence.
+// Values of different types are never equal, and only numbers and strings are ordered.
+func QueryE(data interface{}, expr string) ([]*Var, error) {
+	steps, err := parseJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []interface{}{data}
+	for _, step := range steps {
+		var next []interface{}
+		for _, node := range nodes {
+			candidates := []interface{}{node}
+			if step.descendant {
+				candidates = appendDescendants(candidates, node)
+			}
+			for _, candidate := range candidates {
+				for _, selector := range step.selectors {
+					next = append(next, selector(candidate, data)...)
+				}
+			}
+		}
+		nodes = next
+	}
+	result := make([]*Var, len(nodes))
+	for i, node := range nodes {
+		result[i] = NewVar(node)
+	}
+	return result, nil
+}
+
+// appendDescendants appends every value nested below value in document order.
+func appendDescendants(nodes []interface{}, value interface{}) []interface{} {
+	for _, child := range queryChildren(value) {
+		nodes = append(nodes, child)
+		nodes = appendDescendants(nodes, child)
+	}
+	return nodes
+}
+
+// jsonPathStep is a segment of a JSONPath expression, applied to every current node.
+type jsonPathStep struct {
+	// descendant applies the selectors to the node and all of its descendants.
+	descendant bool
+	selectors  []jsonPathSelector
+}
+
+// jsonPathSelector selects values from node; root is the queried document.
+type jsonPathSelector func(node, root interface{}) []interface{}
+
+// jsonFilter evaluates a filter expression for a candidate value.
+type jsonFilter func(current, root interface{}) bool
+
+// jsonOperand evaluates a filter operand, reporting whether it exists.
+type jsonOperand func(current, root interface{}) (interface{}, bool)
+
+// jsonPathParser is a recursive-descent parser for JSONPath expressions.
+type jsonPathParser struct {
+	expr string
+	pos  int
+}
+
+// parseJSONPath parses expr into steps.
+func parseJSONPath(expr string) ([]jsonPathStep, error) {
+	p := &jsonPathParser{expr: expr}
+	p.skipSpace()
+	if !p.consume("$") {
+		return nil, p.errorf("expected $")
+	}
+	var steps []jsonPathStep
+	for {
+		p.skipSpace()
+		if p.pos == len(p.expr) {
+			return steps, nil
+		}
+		step, err := p.parseStep()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+}
+
+// parseStep parses a .name, ..name or [...] segment.
+func (p *jsonPathParser) parseStep() (jsonPathStep, error) {
+	var step jsonPathStep
+	switch {
+	case p.consume(".."):
+		step.descendant = true
+		if p.peek() == '[' {
+			break
+		}
+		fallthrough
+	case p.consume("."):
+		if p.consume("*") {
+			step.selectors = []jsonPathSelector{selectWildcard}
+			return step, nil
+		}
+		name := p.parseName()
+		if name == "" {
+			return step, p.errorf("expected a name")
+		}
+		step.selectors = []jsonPathSelector{selectName(name)}
+		return step, nil
+	}
+	if !p.consume("[") {
+		return step, p.errorf("expected . or [")
+	}
+	for {
+		p.skipSpace()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return step, err
+		}
+		step.selectors = append(step.selectors, selector)
+		p.skipSpace()
+		if p.consume("]") {
+			return step, nil
+		}
+		if !p.consume(",") {
+			return step, p.errorf("expected , or ]")
+		}
+	}
+}
+
+// parseSelector parses a single selector inside brackets.
+func (p *jsonPathParser) parseSelector() (jsonPathSelector, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		return selectWildcard, nil
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return selectName(name), nil
+	case c == '?':
+		p.pos++
+		p.skipSpace()
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return selectFilter(filter), nil
+	}
+
+	// An index or a slice.
+	var bounds [3]*int
+	part := 0
+	for {
+		p.skipSpace()
+		if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+			n, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			bounds[part] = &n
+		}
+		p.skipSpace()
+		if part == 2 || !p.consume(":") {
+			break
+		}
+		part++
+	}
+	if part == 0 {
+		if bounds[0] == nil {
+			return nil, p.errorf("expected a selector")
+		}
+		return selectIndex(*bounds[0]), nil
+	}
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	if step == 0 {
+		return nil, p.errorf("slice step cannot be zero")
+	}
+	return selectSlice(bounds[0], bounds[1], step), nil
+}
+
+// parseOr parses expr || expr.
+func (p *jsonPathParser) parseOr() (jsonFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(current, root interface{}) bool {
+			return l(current, root) || right(current, root)
+		}
+	}
+}
+
+// parseAnd parses expr && expr.
+func (p *jsonPathParser) parseAnd() (jsonFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(current, root interface{}) bool {
+			return l(current, root) && right(current, root)
+		}
+	}
+}
+
+// parseUnary parses !expr, (expr) and comparisons.
+func (p *jsonPathParser) parseUnary() (jsonFilter, error) {
+	p.skipSpace()
+	if p.peek() == '!' && !strings.HasPrefix(p.expr[p.pos:], "!=") {
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(current, root interface{}) bool {
+			return !inner(current, root)
+		}, nil
+	}
+	if p.consume("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses operand [op operand].
+func (p *jsonPathParser) parseComparison() (jsonFilter, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	var op string
+	for _, candidate := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return func(current, root interface{}) bool {
+			_, ok := left(current, root)
+			return ok
+		}, nil
+	}
+	p.skipSpace()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return func(current, root interface{}) bool {
+		l, lok := left(current, root)
+		r, rok := right(current, root)
+		return compareJSONPath(op, l, lok, r, rok)
+	}, nil
+}
+
+// parseOperand parses an @ or $ path, or a literal.
+func (p *jsonPathParser) parseOperand() (jsonOperand, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		fromRoot := c == '$'
+		path, err := p.parseSingularPath()
+		if err != nil {
+			return nil, err
+		}
+		return func(current, root interface{}) (interface{}, bool) {
+			if fromRoot {
+				current = root
+			}
+			return lookupJSONPath(current, path)
+		}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literalOperand(s), nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.expr) && strings.IndexByte("0123456789.eE+-", p.expr[p.pos]) >= 0 {
+			p.pos++
+		}
+		n := json.Number(p.expr[start:p.pos])
+		if _, err := n.Float64(); err != nil {
+			p.pos = start
+			return nil, p.errorf("invalid number")
+		}
+		return literalOperand(n), nil
+	}
+	switch {
+	case p.consume("true"):
+		return literalOperand(true), nil
+	case p.consume("false"):
+		return literalOperand(false), nil
+	case p.consume("null"):
+		return literalOperand(nil), nil
+	}
+	return nil, p.errorf("expected a path or literal")
+}
+
+// jsonPathKey is a step of a singular path inside a filter.
+type jsonPathKey struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// parseSingularPath parses the .name and [index] steps following @ or $.
+func (p *jsonPathParser) parseSingularPath() ([]jsonPathKey, error) {
+	var path []jsonPathKey
+	for {
+		switch {
+		case p.peek() == '.':
+			p.pos++
+			name := p.parseName()
+			if name == "" {
+				return nil, p.errorf("expected a name")
+			}
+			path = append(path, jsonPathKey{name: name})
+		case p.peek() == '[':
+			p.pos++
+			p.skipSpace()
+			if c := p.peek(); c == '\'' || c == '"' {
+				name, err := p.parseString()
+				if err != nil {
+					return nil, err
+				}
+				path = append(path, jsonPathKey{name: name})
+			} else {
+				index, err := p.parseInt()
+				if err != nil {
+					return nil, err
+				}
+				path = append(path, jsonPathKey{index: index, isIndex: true})
+			}
+			p.skipSpace()
+			if !p.consume("]") {
+				return nil, p.errorf("expected ]")
+			}
+		default:
+			return path, nil
+		}
+	}
+}
+
+// parseName parses an unquoted member name.
+func (p *jsonPathParser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.expr) {
+		c := p.expr[p.pos]
+		if c <= ' ' || strings.IndexByte(".[]()'\",=!<>&|*?@", c) >= 0 {
+			break
+		}
+		p.pos++
+	}
+	return p.expr[start:p.pos]
+}
+
+// parseString parses a single- or double-quoted string with backslash escapes.
+func (p *jsonPathParser) parseString() (string, error) {
+	quote := p.expr[p.pos]
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.expr) {
+		c := p.expr[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && p.pos < len(p.expr):
+			b.WriteByte(p.expr[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+// parseInt parses an optionally negative integer.
+func (p *jsonPathParser) parseInt() (int, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.expr) && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.expr[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("expected an integer")
+	}
+	return n, nil
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos < len(p.expr) {
+		return p.expr[p.pos]
+	}
+	return 0
+}
+
+func (p *jsonPathParser) consume(s string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) skipSpace() {
+	for p.pos < len(p.expr) && (p.expr[p.pos] == ' ' || p.expr[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// errorf reports a syntax error at the current position.
+func (p *jsonPathParser) errorf(format string, args ...interface{}) error {
+	err := fmt.Errorf("%w at offset %d: %s", internal.ErrInvalidFormat, p.pos, fmt.Sprintf(format, args...))
+	return internal.NewConversionError(p.expr, "JSONPath", err)
+}
+
+// selectWildcard selects every element or map value.
+func selectWildcard(node, _ interface{}) []interface{} {
+	return queryChildren(node)
+}
+
+// selectName selects a map key or struct field.
+func selectName(name string) jsonPathSelector {
+	return func(node, _ interface{}) []interface{} {
+		_, m, ok := queryNode(node)
+		if !ok || m == nil {
+			return nil
+		}
+		if value, ok := m[name]; ok {
+			return []interface{}{value}
+		}
+		return nil
+	}
+}
+
+// selectIndex selects a list element, counting from the end when index is negative.
+func selectIndex(index int) jsonPathSelector {
+	return func(node, _ interface{}) []interface{} {
+		list, _, _ := queryNode(node)
+		i := index
+		if i < 0 {
+			i += len(list)
+		}
+		if i < 0 || i >= len(list) {
+			return nil
+		}
+		return []interface{}{list[i]}
+	}
+}
+
+// selectSlice selects list elements from start to end by step, like Python slices.
+func selectSlice(start, end *int, step int) jsonPathSelector {
+	return func(node, _ interface{}) []interface{} {
+		list, _, _ := queryNode(node)
+		n := len(list)
+		normalize := func(bound *int, def int) int {
+			if bound == nil {
+				return def
+			}
+			i := *bound
+			if i < 0 {
+				i += n
+			}
+			return i
+		}
+		var result []interface{}
+		if step > 0 {
+			lo, hi := clampInt(normalize(start, 0), 0, n), clampInt(normalize(end, n), 0, n)
+			for i := lo; i < hi; i += step {
+				result = append(result, list[i])
+			}
+		} else {
+			hi, lo := clampInt(normalize(start, n-1), -1, n-1), clampInt(normalize(end, -n-1), -1, n-1)
+			for i := hi; i > lo; i += step {
+				result = append(result, list[i])
+			}
+		}
+		return result
+	}
+}
+
+// selectFilter selects the elements or map values for which filter holds.
+func selectFilter(filter jsonFilter) jsonPathSelector {
+	return func(node, root interface{}) []interface{} {
+		var result []interface{}
+		for _, child := range queryChildren(node) {
+			if filter(child, root) {
+				result = append(result, child)
+			}
+		}
+		return result
+	}
+}
+
+// literalOperand returns an operand that always evaluates to value.
+func literalOperand(value interface{}) jsonOperand {
+	return func(_, _ interface{}) (interface{}, bool) {
+		return value, true
+	}
+}
+
+// lookupJSONPath follows a singular filter path from value.
+func lookupJSONPath(value interface{}, path []jsonPathKey) (interface{}, bool) {
+	for _, key := range path {
+		var next []interface{}
+		if key.isIndex {
+			next = selectIndex(key.index)(value, nil)
+		} else {
+			next = selectName(key.name)(value, nil)
+		}
+		if len(next) == 0 {
+			return nil, false
+		}
+		value = next[0]
+	}
+	return value, true
+}
+
+// compareJSONPath applies a comparison operator. Missing operands are only equal
+// to each other, and values of different types are never equal.
+func compareJSONPath(op string, l interface{}, lok bool, r interface{}, rok bool) bool {
+	if !lok || !rok {
+		equal := !lok && !rok
+		return (op == "==" && equal) || (op == "!=" && !equal) || (equal && (op == "<=" || op == ">="))
+	}
+	cmp, comparable := compareJSONValues(l, r)
+	switch op {
+	case "==":
+		return comparable && cmp == 0
+	case "!=":
+		return !comparable || cmp != 0
+	}
+	// Only numbers and strings are ordered.
+	if !comparable || cmp == 2 {
+		return false
+	}
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// compareJSONValues returns -1, 0 or 1 for ordered values, 0 or 2 for equal or
+// unequal unordered values, and false when the values have different types.
+func compareJSONValues(l, r interface{}) (int, bool) {
+	if isJSONPathNumber(l) && isJSONPathNumber(r) {
+		lf, lerr := basic.ToFloat64E(l)
+		rf, rerr := basic.ToFloat64E(r)
+		if lerr != nil || rerr != nil {
+			return 0, false
+		}
+		switch {
+		case lf < rf:
+			return -1, true
+		case lf > rf:
+			return 1, true
+		}
+		return 0, true
+	}
+	ls, lstr := l.(string)
+	rs, rstr := r.(string)
+	if lstr && rstr {
+		return strings.Compare(ls, rs), true
+	}
+	if lstr || rstr {
+		return 0, false
+	}
+	if l == nil || r == nil {
+		if l == nil && r == nil {
+			return 0, true
+		}
+		return 0, false
+	}
+	lb, lbool := l.(bool)
+	rb, rbool := r.(bool)
+	if lbool != rbool {
+		return 0, false
+	}
+	if lbool {
+		if lb == rb {
+			return 0, true
+		}
+		return 2, true
+	}
+	if reflect.DeepEqual(l, r) {
+		return 0, true
+	}
+	return 2, true
+}
+
+// isJSONPathNumber reports whether value is a Go or JSON number.
+func isJSONPathNumber(value interface{}) bool {
+	if _, ok := value.(json.Number); ok {
+		return true
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// clampInt limits i to the range [lo, hi].
+func clampInt(i, lo, hi int) int {
+	if i < lo {
+		return lo
+	}
+	if i > hi {
+		return hi
+	}
+	return i
+}
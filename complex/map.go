@@ -1,7 +1,9 @@
 package complex
 
 import (
+	"database/sql/driver"
 	"reflect"
+	"time"
 
 	"github.com/graingo/mconv/basic"
 	"github.com/graingo/mconv/internal"
@@ -14,7 +16,12 @@ func ToMap(value interface{}) map[string]interface{} {
 }
 
 // ToMapE converts any type to map[string]interface{} with error.
+// Structs and pointers to structs are converted to a map of their exported fields,
+// keyed by the same mconv, json, yaml, toml and ini tags that ToStructE reads. Structs
+// without exported fields and structs that encode themselves through json.Marshaler,
+// encoding.TextMarshaler or driver.Valuer, such as time.Time, are unsupported.
 func ToMapE(value interface{}) (map[string]interface{}, error) {
+	value, _ = internal.Unwrap(value)
 	if value == nil {
 		return nil, nil
 	}
@@ -82,6 +89,15 @@ func ToMapE(value interface{}) (map[string]interface{}, error) {
 		return result, nil
 	default:
 		rv := reflect.ValueOf(value)
+		if rv.Kind() == reflect.Ptr && rv.Type().Elem().Kind() == reflect.Struct {
+			if rv.IsNil() {
+				return nil, nil
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() == reflect.Struct {
+			return structToMap(rv)
+		}
 		if rv.Kind() != reflect.Map {
 			return nil, internal.NewConversionError(value, "map", internal.ErrUnsupportedType)
 		}
@@ -98,6 +114,40 @@ func ToMapE(value interface{}) (map[string]interface{}, error) {
 	}
 }
 
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// isOpaqueStruct reports whether structs of type t encode themselves, like time.Time,
+// Decimal or big.Int, so that their fields are not meaningful map entries.
+func isOpaqueStruct(t reflect.Type) bool {
+	for _, iface := range []reflect.Type{jsonMarshalerType, textMarshalerType, valuerType} {
+		if t.Implements(iface) || reflect.PtrTo(t).Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// structToMap returns the exported fields of a struct keyed by their decoder names.
+// Field values are not converted. Structs without decoder fields and structs that
+// encode themselves are reported as unsupported.
+func structToMap(rv reflect.Value) (map[string]interface{}, error) {
+	if isOpaqueStruct(rv.Type()) {
+		return nil, internal.NewConversionError(rv.Interface(), "map", internal.ErrUnsupportedType)
+	}
+	decoder, err := getDecoder(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	if len(decoder.FieldArr) == 0 {
+		return nil, internal.NewConversionError(rv.Interface(), "map", internal.ErrUnsupportedType)
+	}
+	result := make(map[string]interface{}, len(decoder.FieldArr))
+	for _, fieldDecoder := range decoder.FieldArr {
+		result[fieldDecoder.Name] = rv.FieldByIndex(fieldDecoder.Index).Interface()
+	}
+	return result, nil
+}
+
 // ToStringMap converts any type to map[string]string
 func ToStringMap(value interface{}) map[string]string {
 	result, _ := ToStringMapE(value)
@@ -106,6 +156,7 @@ func ToStringMap(value interface{}) map[string]string {
 
 // ToStringMapE converts any type to map[string]string with error
 func ToStringMapE(value interface{}) (map[string]string, error) {
+	value, _ = internal.Unwrap(value)
 	if value == nil {
 		return nil, nil
 	}
@@ -139,7 +190,7 @@ func ToStringMapE(value interface{}) (map[string]string, error) {
 		}
 		return result, nil
 	default:
-		return nil, internal.NewConversionError(value, "map", internal.ErrUnsupportedType)
+		return ToMapTE[string, string](value)
 	}
 }
 
@@ -151,6 +202,7 @@ func ToIntMap(value interface{}) map[string]int {
 
 // ToIntMapE converts any type to map[string]int with error.
 func ToIntMapE(value interface{}) (map[string]int, error) {
+	value, _ = internal.Unwrap(value)
 	if value == nil {
 		return nil, nil
 	}
@@ -196,6 +248,7 @@ func ToFloat64Map(value interface{}) map[string]float64 {
 
 // ToFloat64MapE converts any type to map[string]float64 with error.
 func ToFloat64MapE(value interface{}) (map[string]float64, error) {
+	value, _ = internal.Unwrap(value)
 	if value == nil {
 		return nil, nil
 	}
@@ -232,3 +285,60 @@ func ToFloat64MapE(value interface{}) (map[string]float64, error) {
 		return ToFloat64MapE(m)
 	}
 }
+
+// ToInt64Map converts any type to map[string]int64.
+func ToInt64Map(value interface{}) map[string]int64 {
+	result, _ := ToInt64MapE(value)
+	return result
+}
+
+// ToInt64MapE converts any type to map[string]int64 with error.
+func ToInt64MapE(value interface{}) (map[string]int64, error) {
+	return ToMapTE[string, int64](value)
+}
+
+// ToBoolMap converts any type to map[string]bool.
+func ToBoolMap(value interface{}) map[string]bool {
+	result, _ := ToBoolMapE(value)
+	return result
+}
+
+// ToBoolMapE converts any type to map[string]bool with error.
+func ToBoolMapE(value interface{}) (map[string]bool, error) {
+	return ToMapTE[string, bool](value)
+}
+
+// ToDurationMap converts any type to map[string]time.Duration.
+func ToDurationMap(value interface{}) map[string]time.Duration {
+	result, _ := ToDurationMapE(value)
+	return result
+}
+
+// ToDurationMapE converts any type to map[string]time.Duration with error.
+func ToDurationMapE(value interface{}) (map[string]time.Duration, error) {
+	return ToMapTE[string, time.Duration](value)
+}
+
+// ToStringMapStringSlice converts any type to map[string][]string.
+func ToStringMapStringSlice(value interface{}) map[string][]string {
+	result, _ := ToStringMapStringSliceE(value)
+	return result
+}
+
+// ToStringMapStringSliceE converts any type to map[string][]string with error.
+// It accepts http.Header and url.Values directly; single values become one-element slices.
+func ToStringMapStringSliceE(value interface{}) (map[string][]string, error) {
+	return ToMapTE[string, []string](value)
+}
+
+// ToNestedMap converts any type to map[string]map[string]interface{}.
+func ToNestedMap(value interface{}) map[string]map[string]interface{} {
+	result, _ := ToNestedMapE(value)
+	return result
+}
+
+// ToNestedMapE converts any type to map[string]map[string]interface{} with error.
+// Each value must itself be a map or a struct.
+func ToNestedMapE(value interface{}) (map[string]map[string]interface{}, error) {
+	return ToMapTE[string, map[string]interface{}](value)
+}
@@ -1,9 +1,9 @@
 package complex
 
 import (
+	"fmt"
 	"reflect"
 
-	"github.com/graingo/mconv/basic"
 	"github.com/graingo/mconv/internal"
 )
 
@@ -29,6 +29,7 @@ func ToMapT[K comparable, V any](value interface{}) map[K]V {
 // ToMapTE converts any type to map[K]V with error.
 // This is a generic version of ToMapE that returns a map with key type K and value type V.
 // It uses reflection caching to improve performance for repeated conversions.
+// Keys and values are converted like ToStructE fields; errors name the failing key.
 //
 // Examples:
 //
@@ -40,7 +41,11 @@ func ToMapT[K comparable, V any](value interface{}) map[K]V {
 //
 //	// Convert to map[int]float64 with error handling
 //	floatMap, err := ToMapTE[int, float64](value)
+//
+//	// Decode nested maps into structs
+//	configs, err := ToMapTE[string, Config](value)
 func ToMapTE[K comparable, V any](value interface{}) (map[K]V, error) {
+	value, _ = internal.Unwrap(value)
 	if value == nil {
 		return nil, nil
 	}
@@ -49,103 +54,53 @@ func ToMapTE[K comparable, V any](value interface{}) (map[K]V, error) {
 		return v, nil
 	}
 
-	m, err := ToMapE(value)
-	if err != nil {
-		return nil, internal.NewConversionError(value, "map[K]V", err)
-	}
-
 	kt := reflect.TypeOf((*K)(nil)).Elem()
 	vt := reflect.TypeOf((*V)(nil)).Elem()
 
-	result := make(map[K]V)
-
-	for k, v := range m {
-		var keyConverted K
-		keyRv := reflect.ValueOf(k)
-
-		var keyErr error
-		if keyRv.Type().ConvertibleTo(kt) {
-			keyConverted = keyRv.Convert(kt).Interface().(K)
-		} else {
-			// try to convert via basic types
-			switch kt.Kind() {
-			case reflect.String:
-				keyConverted = any(basic.ToString(k)).(K)
-			case reflect.Int:
-				i, err := basic.ToIntE(k)
-				if err != nil {
-					keyErr = err
-				} else {
-					keyConverted = any(i).(K)
-				}
-			case reflect.Int64:
-				i, err := basic.ToInt64E(k)
-				if err != nil {
-					keyErr = err
-				} else {
-					keyConverted = any(i).(K)
-				}
-			default:
-				keyErr = internal.NewConversionError(k, "K", internal.ErrConversionFailed)
-			}
-		}
-		if keyErr != nil {
-			return nil, keyErr
+	var result map[K]V
+	// Keys and values go through the same engine as ToStructE fields, so any K
+	// supported by a basic converter and any V, including structs and nested
+	// slices or maps, can be produced.
+	set := func(k, v interface{}) error {
+		var key K
+		if k != nil && internal.GetTypeInfo(reflect.TypeOf(k)).IsAssignableTo(kt) {
+			key = k.(K)
+		} else if err := setFieldValue(reflect.ValueOf(&key).Elem(), k, defaultHooks()...); err != nil {
+			return internal.NewConversionError(k, "K", fmt.Errorf("key %v: %w", k, err))
 		}
 
-		// Convert value
-		var valueConverted V
-		var valueErr error
-		if v == nil {
-			result[keyConverted] = valueConverted
-			continue
+		var val V
+		if v != nil && internal.GetTypeInfo(reflect.TypeOf(v)).IsAssignableTo(vt) {
+			val = v.(V)
+		} else if err := setFieldValue(reflect.ValueOf(&val).Elem(), v, defaultHooks()...); err != nil {
+			return internal.NewConversionError(v, "V", fmt.Errorf("key %v: %w", k, err))
 		}
-		valueRv := reflect.ValueOf(v)
-		if valueRv.Type().ConvertibleTo(vt) {
-			valueConverted = valueRv.Convert(vt).Interface().(V)
-		} else {
-			// try to convert via basic types
-			switch vt.Kind() {
-			case reflect.String:
-				valueConverted = any(basic.ToString(v)).(V)
-			case reflect.Int:
-				i, err := basic.ToIntE(v)
-				if err != nil {
-					valueErr = err
-				} else {
-					valueConverted = any(i).(V)
-				}
-			case reflect.Int64:
-				i, err := basic.ToInt64E(v)
-				if err != nil {
-					valueErr = err
-				} else {
-					valueConverted = any(i).(V)
-				}
-			case reflect.Float64:
-				f, err := basic.ToFloat64E(v)
-				if err != nil {
-					valueErr = err
-				} else {
-					valueConverted = any(f).(V)
-				}
-			case reflect.Bool:
-				b, err := basic.ToBoolE(v)
-				if err != nil {
-					valueErr = err
-				} else {
-					valueConverted = any(b).(V)
-				}
-			default:
-				valueErr = internal.NewConversionError(v, "V", internal.ErrConversionFailed)
+
+		result[key] = val
+		return nil
+	}
+
+	// Iterate maps directly so keys keep their original type.
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Map {
+		result = make(map[K]V, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			if err := set(iter.Key().Interface(), iter.Value().Interface()); err != nil {
+				return nil, err
 			}
 		}
-		if valueErr != nil {
-			return nil, valueErr
-		}
-
-		result[keyConverted] = valueConverted
+		return result, nil
 	}
 
+	m, err := ToMapE(value)
+	if err != nil {
+		return nil, internal.NewConversionError(value, "map[K]V", err)
+	}
+	result = make(map[K]V, len(m))
+	for k, v := range m {
+		if err := set(k, v); err != nil {
+			return nil, err
+		}
+	}
 	return result, nil
 }
@@ -0,0 +1,420 @@
+package complex
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/graingo/mconv/basic"
+)
+
+// SliceStrategy controls how slices present in both the destination and a source are merged.
+type SliceStrategy int
+
+const (
+	// SliceReplace replaces the destination slice with the source slice.
+	SliceReplace SliceStrategy = iota
+	// SliceAppend appends the source elements to the destination slice.
+	SliceAppend
+	// SliceAppendUnique appends the source elements that are not already present.
+	SliceAppendUnique
+	// SliceMergeByKey deep-merges map or struct elements that share the value of
+	// MergeOptions.MergeKey and appends the rest.
+	SliceMergeByKey
+)
+
+// MergeOptions configures MergeWithOptionsE.
+type MergeOptions struct {
+	// Slices is the strategy for slices present on both sides.
+	Slices SliceStrategy
+	// MergeKey is the element key compared by SliceMergeByKey, such as "id".
+	MergeKey string
+	// OverwriteWithEmpty lets nil source values and zero-valued fields of struct sources
+	// replace existing values. By default they are skipped, so unset struct fields do not
+	// clear earlier layers. Zero values present in map sources, such as debug: false from
+	// an environment layer, always replace existing values.
+	OverwriteWithEmpty bool
+	// AllowTypeChange lets a source value replace a destination value of a different
+	// kind, such as a string replacing an int or a scalar replacing a slice. By default
+	// that is reported as an error; numbers of different types never conflict.
+	AllowTypeChange bool
+}
+
+// Merge deep-merges srcs into dst in order, ignoring errors.
+func Merge(dst interface{}, srcs ...interface{}) {
+	_ = MergeE(dst, srcs...)
+}
+
+// MergeE deep-merges srcs into dst in order with default options, with error.
+// See MergeWithOptionsE.
+func MergeE(dst interface{}, srcs ...interface{}) error {
+	_, err := MergeWithOptionsE(dst, MergeOptions{}, srcs...)
+	return err
+}
+
+// MergeWithOptionsE deep-merges srcs into dst in order, so later sources win.
+// dst must be a map[string]interface{}, a pointer to one, or a pointer to a struct.
+// Sources may be any maps or structs; nested maps and structs are merged key by key,
+// slices according to opts.Slices, and other values replace what is in dst. A key
+// present in a map source replaces the existing value even when it holds false, 0 or
+// "", while nil values and zero-valued struct fields are skipped, see
+// MergeOptions.OverwriteWithEmpty.
+// Merging a map or struct with a non-map value is reported as an error, and so is
+// replacing a value with one of a different kind unless opts.AllowTypeChange is set.
+// When no source contributes anything, dst is left unchanged.
+//
+// The returned map records which source provided each final leaf, keyed by its
+// dotted path and holding the index into srcs, or -1 for values kept from dst.
+func MergeWithOptionsE(dst interface{}, opts MergeOptions, srcs ...interface{}) (map[string]int, error) {
+	if dst == nil {
+		return nil, errors.New("merge destination cannot be nil")
+	}
+
+	var (
+		merged map[string]interface{}
+		err    error
+	)
+	dstRv := reflect.ValueOf(dst)
+	switch {
+	case dstRv.Kind() == reflect.Map:
+		if dstRv.IsNil() {
+			return nil, errors.New("merge destination map cannot be nil")
+		}
+		merged, err = ToMapE(dst)
+	case dstRv.Kind() == reflect.Ptr && !dstRv.IsNil() && dstRv.Elem().Kind() == reflect.Struct:
+		merged, err = structToMap(dstRv.Elem())
+	case dstRv.Kind() == reflect.Ptr && !dstRv.IsNil() && dstRv.Elem().Kind() == reflect.Map:
+		merged, err = ToMapE(dstRv.Elem().Interface())
+		if merged == nil {
+			merged = make(map[string]interface{})
+		}
+	default:
+		return nil, fmt.Errorf("merge destination must be a map or a pointer to a map or struct, but got %T", dst)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// ToMapE returns a map[string]interface{} destination as is; copy it so that the
+	// write-back below does not clear the map it reads from.
+	merged = copyMergeMap(merged)
+
+	m := &merger{opts: opts, provenance: make(map[string]int)}
+	m.record("", merged, -1)
+	for i, src := range srcs {
+		if src == nil {
+			continue
+		}
+		if _, ok := asMergeMap(src); !ok {
+			return nil, fmt.Errorf("merge source %d: expected a map or struct, but got %T", i, src)
+		}
+		result, err := m.merge("", merged, src, true, false, i)
+		if err != nil {
+			return nil, fmt.Errorf("merge source %d: %w", i, err)
+		}
+		merged = result.(map[string]interface{})
+	}
+
+	// Write the result back into the destination.
+	switch {
+	case dstRv.Kind() == reflect.Map:
+		if target, ok := dst.(map[string]interface{}); ok {
+			for k := range target {
+				delete(target, k)
+			}
+			for k, v := range merged {
+				target[k] = v
+			}
+			break
+		}
+		converted := reflect.New(dstRv.Type())
+		if err := setFieldValue(converted.Elem(), merged, defaultHooks()...); err != nil {
+			return nil, err
+		}
+		for _, k := range dstRv.MapKeys() {
+			dstRv.SetMapIndex(k, reflect.Value{})
+		}
+		iter := converted.Elem().MapRange()
+		for iter.Next() {
+			dstRv.SetMapIndex(iter.Key(), iter.Value())
+		}
+	case dstRv.Elem().Kind() == reflect.Struct:
+		if err := ToStructE(merged, dst); err != nil {
+			return nil, err
+		}
+	default:
+		target := reflect.New(dstRv.Elem().Type()).Elem()
+		if err := setFieldValue(target, merged, defaultHooks()...); err != nil {
+			return nil, err
+		}
+		dstRv.Elem().Set(target)
+	}
+	return m.provenance, nil
+}
+
+// merger holds the state of a single MergeWithOptionsE call.
+type merger struct {
+	opts MergeOptions
+	// provenance is nil while merging slice elements, whose origin is tracked per slice.
+	provenance map[string]int
+	// keyPath is the MergeKey while merging slice elements; elements are matched by the
+	// string form of their keys, so the key values may differ in type.
+	keyPath string
+}
+
+// merge combines dst and src found at path and returns the result.
+// exists reports whether dst holds a value, as opposed to a missing key, and
+// structField whether src is a struct field, whose zero value means unset.
+func (m *merger) merge(path string, dst, src interface{}, exists, structField bool, index int) (interface{}, error) {
+	if !m.opts.OverwriteWithEmpty && (src == nil || structField && isEmptyMergeValue(src)) {
+		if exists || src == nil {
+			return dst, nil
+		}
+	}
+	if !exists || dst == nil {
+		m.forget(path)
+		m.record(path, src, index)
+		return src, nil
+	}
+
+	dstMap, dstIsMap := asMergeMap(dst)
+	srcMap, srcIsMap := asMergeMap(src)
+	switch {
+	case dstIsMap && srcIsMap:
+		result := make(map[string]interface{}, len(dstMap)+len(srcMap))
+		for k, v := range dstMap {
+			result[k] = v
+		}
+		fromStruct := isMergeStruct(src)
+		for k, v := range srcMap {
+			existing, ok := result[k]
+			if !ok && v == nil && !m.opts.OverwriteWithEmpty {
+				continue
+			}
+			value, err := m.merge(joinMergePath(path, k), existing, v, ok, fromStruct, index)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = value
+		}
+		return result, nil
+	case dstIsMap || srcIsMap:
+		return nil, fmt.Errorf("key %q: cannot merge %T into %T", path, src, dst)
+	case src != nil && !m.opts.AllowTypeChange && path != m.keyPath && mergeKind(dst) != mergeKind(src):
+		return nil, fmt.Errorf("key %q: cannot merge %T into %T", path, src, dst)
+	}
+
+	if isMergeSlice(dst) && isMergeSlice(src) && m.opts.Slices != SliceReplace {
+		result, err := m.mergeSlices(dst, src, index)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", path, err)
+		}
+		m.forget(path)
+		m.record(path, result, index)
+		return result, nil
+	}
+
+	m.forget(path)
+	m.record(path, src, index)
+	return src, nil
+}
+
+// mergeSlices combines two slices according to the slice strategy.
+func (m *merger) mergeSlices(dst, src interface{}, index int) ([]interface{}, error) {
+	dstSlice, err := ToSliceE(dst)
+	if err != nil {
+		return nil, err
+	}
+	srcSlice, err := ToSliceE(src)
+	if err != nil {
+		return nil, err
+	}
+	result := append(make([]interface{}, 0, len(dstSlice)+len(srcSlice)), dstSlice...)
+
+	switch m.opts.Slices {
+	case SliceAppend:
+		return append(result, srcSlice...), nil
+	case SliceAppendUnique:
+		for _, v := range srcSlice {
+			if !containsMergeValue(result, v) {
+				result = append(result, v)
+			}
+		}
+		return result, nil
+	case SliceMergeByKey:
+		if m.opts.MergeKey == "" {
+			return nil, errors.New("SliceMergeByKey requires a MergeKey")
+		}
+		// Elements are merged without recording provenance; the slice is recorded as a whole.
+		elements := &merger{opts: m.opts, keyPath: m.opts.MergeKey}
+		for _, v := range srcSlice {
+			key, ok := mergeElementKey(v, m.opts.MergeKey)
+			pos := -1
+			if ok {
+				for i, existing := range result {
+					if k, found := mergeElementKey(existing, m.opts.MergeKey); found && k == key {
+						pos = i
+						break
+					}
+				}
+			}
+			if pos < 0 {
+				result = append(result, v)
+				continue
+			}
+			merged, err := elements.merge("", result[pos], v, true, false, index)
+			if err != nil {
+				return nil, err
+			}
+			result[pos] = merged
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unknown slice strategy %d", m.opts.Slices)
+	}
+}
+
+// record stores the source index for every leaf of value below path.
+func (m *merger) record(path string, value interface{}, index int) {
+	if m.provenance == nil {
+		return
+	}
+	if mv, ok := asMergeMap(value); ok && len(mv) > 0 {
+		for k, v := range mv {
+			m.record(joinMergePath(path, k), v, index)
+		}
+		return
+	}
+	if path != "" {
+		m.provenance[path] = index
+	}
+}
+
+// forget removes the provenance of path and everything below it.
+func (m *merger) forget(path string) {
+	if m.provenance == nil {
+		return
+	}
+	if path == "" {
+		for k := range m.provenance {
+			delete(m.provenance, k)
+		}
+		return
+	}
+	delete(m.provenance, path)
+	prefix := path + "."
+	for k := range m.provenance {
+		if strings.HasPrefix(k, prefix) {
+			delete(m.provenance, k)
+		}
+	}
+}
+
+// joinMergePath appends key to a dotted path.
+func joinMergePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// asMergeMap returns value as a map if it is a map, or a struct with exported fields.
+// Structs without exported fields, such as time.Time, are treated as plain values.
+func asMergeMap(value interface{}) (map[string]interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		m, err := ToMapE(value)
+		return m, err == nil
+	case reflect.Struct:
+		m, err := structToMap(rv)
+		if err != nil || len(m) == 0 {
+			return nil, false
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+// copyMergeMap returns a shallow copy of m.
+func copyMergeMap(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+// mergeKind classifies a non-map value for type conflict checks. All numbers share
+// one kind, and slices and arrays another.
+func mergeKind(value interface{}) reflect.Kind {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	case reflect.Array:
+		return reflect.Slice
+	}
+	return rv.Kind()
+}
+
+// isMergeSlice reports whether value is a slice or array other than []byte.
+func isMergeSlice(value interface{}) bool {
+	rv := reflect.ValueOf(value)
+	return (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8
+}
+
+// isMergeStruct reports whether value is a struct or a pointer to one.
+func isMergeStruct(value interface{}) bool {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	return rv.Kind() == reflect.Struct
+}
+
+// isEmptyMergeValue reports whether value is nil or the zero value of its type.
+func isEmptyMergeValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	return reflect.ValueOf(value).IsZero()
+}
+
+// containsMergeValue reports whether values holds an element deeply equal to v.
+func containsMergeValue(values []interface{}, v interface{}) bool {
+	for _, existing := range values {
+		if reflect.DeepEqual(existing, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeElementKey returns the string form of the key field of a map or struct element.
+func mergeElementKey(element interface{}, key string) (string, bool) {
+	m, ok := asMergeMap(element)
+	if !ok {
+		return "", false
+	}
+	v, ok := m[key]
+	if !ok || v == nil {
+		return "", false
+	}
+	s, err := basic.ToStringE(v)
+	return s, err == nil
+}
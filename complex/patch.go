@@ -0,0 +1,595 @@
+package complex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graingo/mconv/basic"
+	"github.com/graingo/mconv/internal"
+)
+
+// ErrPatchTestFailed is returned by ApplyJSONPatch when a test operation does not match.
+var ErrPatchTestFailed = errors.New("patch test failed")
+
+// PatchOperation is a single RFC 6902 JSON Patch operation.
+type PatchOperation struct {
+	// Op is one of "add", "remove", "replace", "move", "copy" and "test".
+	Op string `json:"op"`
+	// Path is the JSON Pointer of the target location.
+	Path string `json:"path"`
+	// From is the JSON Pointer of the source location for move and copy.
+	From string `json:"from,omitempty"`
+	// Value is the value for add, replace and test.
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Value is written for add, replace and test
+// even when it is null or zero, and omitted for the other operations.
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	type operation PatchOperation
+	if op.Op != "add" && op.Op != "replace" && op.Op != "test" {
+		op.Value = nil
+		return json.Marshal(operation(op))
+	}
+	return json.Marshal(struct {
+		operation
+		Value interface{} `json:"value"`
+	}{operation(op), op.Value})
+}
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch to doc and returns the result.
+// Objects in patch are merged key by key, null values remove keys, and anything else,
+// including arrays, replaces the target. doc and patch may be decoded JSON such as the
+// result of ToMapFromJSONE, or any maps, slices and structs, which are read like ToMapE
+// reads them; doc is not modified.
+func ApplyMergePatch(doc, patch interface{}) (interface{}, error) {
+	target, err := toPatchValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	p, err := toPatchValue(patch)
+	if err != nil {
+		return nil, err
+	}
+	return mergePatch(target, p), nil
+}
+
+// ApplyMergePatchJSON applies a JSON Merge Patch to a JSON document.
+func ApplyMergePatchJSON(doc, patch string) (string, error) {
+	d, err := decodePatchJSON(doc)
+	if err != nil {
+		return "", err
+	}
+	p, err := decodePatchJSON(patch)
+	if err != nil {
+		return "", err
+	}
+	return ToJSONE(mergePatch(d, p))
+}
+
+// mergePatch implements the MergePatch function of RFC 7386 on copied values.
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch to doc and returns the result.
+// patch may be a []PatchOperation, decoded JSON such as a []interface{} of operation
+// objects, or JSON text as a string or []byte. The patch is applied atomically: doc is
+// never modified, and if any operation fails the error reports its index and no result
+// is returned. A failing test operation is reported as ErrPatchTestFailed, and a missing
+// location as ErrPathNotFound.
+func ApplyJSONPatch(doc, patch interface{}) (interface{}, error) {
+	ops, err := parsePatchOperations(patch)
+	if err != nil {
+		return nil, err
+	}
+	result, err := toPatchValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		if result, err = applyPatchOperation(result, op); err != nil {
+			return nil, fmt.Errorf("patch operation %d (%s %q): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return result, nil
+}
+
+// ApplyJSONPatchJSON applies a JSON Patch to a JSON document.
+func ApplyJSONPatchJSON(doc, patch string) (string, error) {
+	d, err := decodePatchJSON(doc)
+	if err != nil {
+		return "", err
+	}
+	result, err := ApplyJSONPatch(d, patch)
+	if err != nil {
+		return "", err
+	}
+	return ToJSONE(result)
+}
+
+// Diff returns the JSON Patch that turns a into b. Objects are compared key by key
+// and arrays element by element; other values that differ are replaced. Numbers are
+// equal when their values are, whatever their Go types.
+func Diff(a, b interface{}) ([]PatchOperation, error) {
+	from, err := toPatchValue(a)
+	if err != nil {
+		return nil, err
+	}
+	to, err := toPatchValue(b)
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]PatchOperation, 0)
+	return appendDiff(ops, "", from, to), nil
+}
+
+// DiffJSON returns the JSON Patch that turns JSON document a into b, as JSON.
+func DiffJSON(a, b string) (string, error) {
+	from, err := decodePatchJSON(a)
+	if err != nil {
+		return "", err
+	}
+	to, err := decodePatchJSON(b)
+	if err != nil {
+		return "", err
+	}
+	ops, err := Diff(from, to)
+	if err != nil {
+		return "", err
+	}
+	return ToJSONE(ops)
+}
+
+// decodePatchJSON decodes a JSON document, keeping numbers as json.Number so that
+// they are written back unchanged.
+func decodePatchJSON(s string) (interface{}, error) {
+	var value interface{}
+	if err := decodeJSON([]byte(s), &value, []JSONOption{JSONUseNumber()}); err != nil {
+		return nil, internal.NewConversionError(s, "JSON", err)
+	}
+	return value, nil
+}
+
+// patchOperation is a parsed PatchOperation with its pointers split into tokens.
+type patchOperation struct {
+	PatchOperation
+	path []string
+	from []string
+}
+
+// parsePatchOperations reads a JSON Patch in any of the forms ApplyJSONPatch accepts.
+func parsePatchOperations(patch interface{}) ([]patchOperation, error) {
+	var elements []interface{}
+	switch p := patch.(type) {
+	case nil:
+		return nil, nil
+	case []PatchOperation:
+		ops := make([]patchOperation, len(p))
+		for i, op := range p {
+			parsed, err := newPatchOperation(op, true)
+			if err != nil {
+				return nil, fmt.Errorf("patch operation %d: %w", i, err)
+			}
+			ops[i] = parsed
+		}
+		return ops, nil
+	case string, []byte:
+		data, _ := basic.ToStringE(p)
+		if err := decodeJSON([]byte(data), &elements, []JSONOption{JSONUseNumber()}); err != nil {
+			return nil, internal.NewConversionError(patch, "JSON patch", err)
+		}
+	default:
+		list, err := ToSliceE(patch)
+		if err != nil {
+			return nil, internal.NewConversionError(patch, "JSON patch", err)
+		}
+		elements = list
+	}
+
+	ops := make([]patchOperation, len(elements))
+	for i, element := range elements {
+		if op, ok := element.(PatchOperation); ok {
+			parsed, err := newPatchOperation(op, true)
+			if err != nil {
+				return nil, fmt.Errorf("patch operation %d: %w", i, err)
+			}
+			ops[i] = parsed
+			continue
+		}
+		m, err := ToMapE(element)
+		if err != nil || m == nil {
+			return nil, fmt.Errorf("patch operation %d: expected an object, but got %T", i, element)
+		}
+		var op PatchOperation
+		op.Op, _ = m["op"].(string)
+		op.Path, _ = m["path"].(string)
+		op.From, _ = m["from"].(string)
+		value, hasValue := m["value"]
+		op.Value = value
+		if _, ok := m["path"].(string); !ok {
+			return nil, fmt.Errorf("patch operation %d: missing path", i)
+		}
+		if _, ok := m["from"].(string); !ok && (op.Op == "move" || op.Op == "copy") {
+			return nil, fmt.Errorf("patch operation %d: missing from", i)
+		}
+		parsed, err := newPatchOperation(op, hasValue)
+		if err != nil {
+			return nil, fmt.Errorf("patch operation %d: %w", i, err)
+		}
+		ops[i] = parsed
+	}
+	return ops, nil
+}
+
+// newPatchOperation validates op and parses its pointers. hasValue reports whether
+// the operation object had a value member, which may be null.
+func newPatchOperation(op PatchOperation, hasValue bool) (patchOperation, error) {
+	parsed := patchOperation{PatchOperation: op}
+	switch op.Op {
+	case "add", "replace", "test":
+		if !hasValue {
+			return parsed, fmt.Errorf("%s operation requires a value", op.Op)
+		}
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return parsed, err
+		}
+		parsed.from = from
+	case "remove":
+	default:
+		return parsed, fmt.Errorf("unknown operation %q", op.Op)
+	}
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return parsed, err
+	}
+	parsed.path = path
+	return parsed, nil
+}
+
+// applyPatchOperation applies a single operation to doc and returns the new document.
+func applyPatchOperation(doc interface{}, op patchOperation) (interface{}, error) {
+	switch op.Op {
+	case "add":
+		value, err := toPatchValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, op.path, value)
+	case "remove":
+		result, _, err := patchRemove(doc, op.path)
+		return result, err
+	case "replace":
+		value, err := toPatchValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := patchGet(doc, op.path); err != nil {
+			return nil, err
+		}
+		result, _, err := patchRemove(doc, op.path)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(result, op.path, value)
+	case "move":
+		if op.Path == op.From {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, errors.New("cannot move a value into one of its children")
+		}
+		result, value, err := patchRemove(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(result, op.path, value)
+	case "copy":
+		value, err := patchGet(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		// Copy so that later operations on either location do not affect the other.
+		value, err = toPatchValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, op.path, value)
+	default: // test
+		value, err := patchGet(doc, op.path)
+		if err != nil {
+			return nil, err
+		}
+		expected, err := toPatchValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !patchEqual(value, expected) {
+			return nil, fmt.Errorf("%w: %v is not %v", ErrPatchTestFailed, value, expected)
+		}
+		return doc, nil
+	}
+}
+
+// patchGet returns the value at path.
+func patchGet(doc interface{}, path []string) (interface{}, error) {
+	current := doc
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrPathNotFound, token)
+			}
+			current = next
+		case []interface{}:
+			index, ok := pointerIndex(token)
+			if !ok || index >= len(node) {
+				return nil, fmt.Errorf("%w: index %q", ErrPathNotFound, token)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%w: cannot look up %q in %T", ErrPathNotFound, token, current)
+		}
+	}
+	return current, nil
+}
+
+// patchUpdate calls fn with the parent of the location at path and its last token,
+// and stores the parent returned by fn back into the document.
+func patchUpdate(doc interface{}, path []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	token := path[0]
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrPathNotFound, token)
+		}
+		updated, err := patchUpdate(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = updated
+		return node, nil
+	case []interface{}:
+		index, ok := pointerIndex(token)
+		if !ok || index >= len(node) {
+			return nil, fmt.Errorf("%w: index %q", ErrPathNotFound, token)
+		}
+		updated, err := patchUpdate(node[index], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = updated
+		return node, nil
+	}
+	return nil, fmt.Errorf("%w: cannot look up %q in %T", ErrPathNotFound, token, doc)
+}
+
+// patchAdd adds value at path, inserting into arrays; "-" appends to an array.
+func patchAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return patchUpdate(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			if token == "-" {
+				return append(node, value), nil
+			}
+			index, ok := pointerIndex(token)
+			if !ok || index > len(node) {
+				return nil, fmt.Errorf("index %q out of range for array of length %d", token, len(node))
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("%w: cannot add %q to %T", ErrPathNotFound, token, parent)
+	})
+}
+
+// patchRemove removes the value at path and returns the new document and the removed value.
+func patchRemove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+	var removed interface{}
+	result, err := patchUpdate(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrPathNotFound, token)
+			}
+			removed = value
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			index, ok := pointerIndex(token)
+			if !ok || index >= len(node) {
+				return nil, fmt.Errorf("%w: index %q", ErrPathNotFound, token)
+			}
+			removed = node[index]
+			return append(node[:index:index], node[index+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: cannot remove %q from %T", ErrPathNotFound, token, parent)
+	})
+	return result, removed, err
+}
+
+// toPatchValue returns a deep copy of value made of map[string]interface{},
+// []interface{} and scalar values. Types implementing json.Marshaler or
+// encoding.TextMarshaler, such as time.Time, are copied through their JSON form.
+func toPatchValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Type() != jsonNumberType && implementsJSONMarshaler(rv) {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		var generic interface{}
+		if err := decodeJSON(data, &generic, []JSONOption{JSONUseNumber()}); err != nil {
+			return nil, err
+		}
+		return generic, nil
+	}
+	list, m, ok := queryNode(value)
+	switch {
+	case !ok:
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return nil, nil
+			}
+			return toPatchValue(rv.Elem().Interface())
+		}
+		return value, nil
+	case list != nil:
+		result := make([]interface{}, len(list))
+		for i, element := range list {
+			copied, err := toPatchValue(element)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = copied
+		}
+		return result, nil
+	default:
+		result := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			copied, err := toPatchValue(v)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = copied
+		}
+		return result, nil
+	}
+}
+
+// appendDiff appends the operations that turn a into b at path.
+func appendDiff(ops []PatchOperation, path string, a, b interface{}) []PatchOperation {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := path + "/" + escapePointerToken(k)
+			aValue, inA := av[k]
+			bValue, inB := bv[k]
+			switch {
+			case !inB:
+				ops = append(ops, PatchOperation{Op: "remove", Path: child})
+			case !inA:
+				ops = append(ops, PatchOperation{Op: "add", Path: child, Value: bValue})
+			default:
+				ops = appendDiff(ops, child, aValue, bValue)
+			}
+		}
+		return ops
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		common := len(av)
+		if len(bv) < common {
+			common = len(bv)
+		}
+		for i := 0; i < common; i++ {
+			ops = appendDiff(ops, path+"/"+strconv.Itoa(i), av[i], bv[i])
+		}
+		for i := common; i < len(bv); i++ {
+			ops = append(ops, PatchOperation{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: bv[i]})
+		}
+		// Remove from the end so that earlier indices stay valid.
+		for i := len(av) - 1; i >= common; i-- {
+			ops = append(ops, PatchOperation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		return ops
+	}
+	if !patchEqual(a, b) {
+		ops = append(ops, PatchOperation{Op: "replace", Path: path, Value: b})
+	}
+	return ops
+}
+
+// escapePointerToken escapes "~" and "/" in a JSON Pointer reference token.
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// patchEqual reports whether two values built by toPatchValue are equal as JSON values.
+func patchEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			other, ok := bv[k]
+			if !ok || !patchEqual(v, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !patchEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	cmp, comparable := compareJSONValues(a, b)
+	return comparable && cmp == 0
+}
@@ -0,0 +1,239 @@
+package complex
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// ErrPathNotFound is returned by GetE when a path does not exist in the data.
+var ErrPathNotFound = errors.New("path not found")
+
+// Get returns the value at path inside nested maps, structs, slices and arrays.
+// Path segments are separated by dots, and slice elements are addressed by a
+// numeric segment or an index, for example "users.0.address.city" or
+// "users[0].address.city". Map keys fall back to case-insensitive matching.
+// A missing path yields a Var wrapping nil.
+func Get(data interface{}, path string) *Var {
+	result, err := GetE(data, path)
+	if err != nil {
+		return NewVar(nil)
+	}
+	return result
+}
+
+// GetE returns the value at path with error. See Get for the path syntax.
+func GetE(data interface{}, path string) (*Var, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := lookupPath(data, segments)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrPathNotFound, path)
+	}
+	return NewVar(value), nil
+}
+
+// Set stores value at path inside data, creating intermediate containers as needed.
+// Missing containers are created as []interface{} when the next segment is numeric
+// or an index, and as map[string]interface{} otherwise; slices are grown with nil
+// elements, by at most 1024 elements past their end per call, so that an index such
+// as "items.999999999" is an error rather than a huge allocation. data must be a map,
+// or a pointer to a map, slice, struct or interface{} so that the change is visible
+// to the caller. Struct fields are converted with the same rules as ToStructE.
+func Set(data interface{}, path string, value interface{}) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return errors.New("path cannot be empty")
+	}
+
+	rv := reflect.ValueOf(data)
+	switch {
+	case rv.Kind() == reflect.Map && !rv.IsNil():
+		_, err = setPath(rv, segments, value)
+	case rv.Kind() == reflect.Ptr && !rv.IsNil():
+		var updated reflect.Value
+		updated, err = setPath(rv.Elem(), segments, value)
+		if err == nil {
+			err = assignPathValue(rv.Elem(), updated)
+		}
+	default:
+		return fmt.Errorf("set target must be a non-nil map or pointer, but got %T", data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set %q: %w", path, err)
+	}
+	return nil
+}
+
+// maxSetGrowth is the number of elements past its end by which Set grows a slice.
+const maxSetGrowth = 1024
+
+// parsePath parses a Get/Set path using the same syntax as mconv tag paths.
+func parsePath(path string) ([]internal.PathSegment, error) {
+	if path == "" {
+		return nil, nil
+	}
+	segments := parseTagPath(path)
+	if segments == nil {
+		return nil, internal.NewConversionError(path, "path", internal.ErrInvalidFormat)
+	}
+	return segments, nil
+}
+
+// setPath stores value below container and returns the container to store in its parent,
+// which differs from container when it had to be created or grown.
+func setPath(container reflect.Value, path []internal.PathSegment, value interface{}) (reflect.Value, error) {
+	if len(path) == 0 {
+		return reflect.ValueOf(&value).Elem(), nil
+	}
+	seg := path[0]
+
+	// Look through interfaces and create missing containers.
+	if container.Kind() == reflect.Interface {
+		if container.IsNil() {
+			if _, ok := segmentIndex(seg); ok {
+				container = reflect.ValueOf([]interface{}{})
+			} else {
+				container = reflect.ValueOf(map[string]interface{}{})
+			}
+		} else {
+			container = container.Elem()
+		}
+	}
+	if container.Kind() == reflect.Ptr {
+		if container.IsNil() {
+			if !container.CanSet() {
+				return container, errors.New("cannot set through a nil pointer")
+			}
+			container.Set(reflect.New(container.Type().Elem()))
+		}
+		updated, err := setPath(container.Elem(), path, value)
+		if err != nil {
+			return container, err
+		}
+		return container, assignPathValue(container.Elem(), updated)
+	}
+
+	switch container.Kind() {
+	case reflect.Map:
+		if seg.IsIndex || container.Type().Key().Kind() != reflect.String {
+			return container, fmt.Errorf("cannot use segment %v on %s", describeSegment(seg), container.Type())
+		}
+		if container.IsNil() {
+			container = reflect.MakeMap(container.Type())
+		}
+		key := reflect.ValueOf(seg.Key).Convert(container.Type().Key())
+		child := reflect.New(container.Type().Elem()).Elem()
+		if existing := container.MapIndex(key); existing.IsValid() {
+			child.Set(existing)
+		}
+		updated, err := setPath(child, path[1:], value)
+		if err != nil {
+			return container, err
+		}
+		if err := assignPathValue(child, updated); err != nil {
+			return container, err
+		}
+		container.SetMapIndex(key, child)
+		return container, nil
+	case reflect.Slice, reflect.Array:
+		index, ok := segmentIndex(seg)
+		if !ok {
+			return container, fmt.Errorf("cannot use segment %v on %s", describeSegment(seg), container.Type())
+		}
+		if index >= container.Len() {
+			if container.Kind() == reflect.Array {
+				return container, fmt.Errorf("index %d out of range for %s", index, container.Type())
+			}
+			if index-container.Len() >= maxSetGrowth {
+				return container, fmt.Errorf("index %d out of range: %s of length %d grows by at most %d elements",
+					index, container.Type(), container.Len(), maxSetGrowth)
+			}
+			grown := reflect.MakeSlice(container.Type(), index+1, index+1)
+			reflect.Copy(grown, container)
+			container = grown
+		} else if !container.CanSet() {
+			// Copy arrays and slices reached through interfaces so that they can be modified.
+			copied := reflect.New(container.Type()).Elem()
+			copied.Set(container)
+			container = copied
+		}
+		child := container.Index(index)
+		updated, err := setPath(child, path[1:], value)
+		if err != nil {
+			return container, err
+		}
+		if err := assignPathValue(child, updated); err != nil {
+			return container, err
+		}
+		return container, nil
+	case reflect.Struct:
+		if seg.IsIndex {
+			return container, fmt.Errorf("cannot use segment %v on %s", describeSegment(seg), container.Type())
+		}
+		if !container.CanAddr() {
+			copied := reflect.New(container.Type()).Elem()
+			copied.Set(container)
+			container = copied
+		}
+		decoder, err := getDecoder(container.Type())
+		if err != nil {
+			return container, err
+		}
+		fieldDecoder, ok := decoder.Fields[seg.Key]
+		if !ok {
+			for name, fd := range decoder.Fields {
+				if strings.EqualFold(name, seg.Key) {
+					fieldDecoder, ok = fd, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return container, fmt.Errorf("%s has no field %q", container.Type(), seg.Key)
+		}
+		field := container.FieldByIndex(fieldDecoder.Index)
+		updated, err := setPath(field, path[1:], value)
+		if err != nil {
+			return container, err
+		}
+		if err := assignPathValue(field, updated); err != nil {
+			return container, err
+		}
+		return container, nil
+	default:
+		return container, fmt.Errorf("cannot use segment %v on %s", describeSegment(seg), container.Type())
+	}
+}
+
+// assignPathValue stores updated into target, converting it when the types differ.
+func assignPathValue(target, updated reflect.Value) error {
+	if updated.Kind() == reflect.Interface {
+		if updated.IsNil() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		updated = updated.Elem()
+	}
+	if updated.Type().AssignableTo(target.Type()) {
+		target.Set(updated)
+		return nil
+	}
+	return setFieldValue(target, updated.Interface(), defaultHooks()...)
+}
+
+// describeSegment formats a path segment for error messages.
+func describeSegment(seg internal.PathSegment) string {
+	if seg.IsIndex {
+		return fmt.Sprintf("[%d]", seg.Index)
+	}
+	return fmt.Sprintf("%q", seg.Key)
+}
@@ -0,0 +1,153 @@
+package complex
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// GetPointer returns the value referenced by an RFC 6901 JSON Pointer such as
+// "/users/0/name". Unlike Get, keys are matched exactly and "~1" and "~0" stand
+// for "/" and "~". A missing or invalid pointer yields a Var wrapping nil. The result
+// can be passed directly to the typed converters, as in ToIntE(GetPointer(data, "/age")).
+func GetPointer(data interface{}, pointer string) *Var {
+	result, err := GetPointerE(data, pointer)
+	if err != nil {
+		return NewVar(nil)
+	}
+	return result
+}
+
+// GetPointerE returns the value referenced by an RFC 6901 JSON Pointer with error.
+// The empty pointer references the whole document.
+func GetPointerE(data interface{}, pointer string) (*Var, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	current := data
+	for _, token := range tokens {
+		list, m, ok := queryNode(current)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrPathNotFound, pointer)
+		}
+		if list != nil {
+			index, ok := pointerIndex(token)
+			if !ok || index >= len(list) {
+				return nil, fmt.Errorf("%w: %q", ErrPathNotFound, pointer)
+			}
+			current = list[index]
+			continue
+		}
+		next, ok := m[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrPathNotFound, pointer)
+		}
+		current = next
+	}
+	return NewVar(current), nil
+}
+
+// parsePointer splits a JSON Pointer into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, internal.NewConversionError(pointer, "JSON pointer", internal.ErrInvalidFormat)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		if !strings.Contains(token, "~") {
+			continue
+		}
+		var b strings.Builder
+		for j := 0; j < len(token); j++ {
+			if token[j] != '~' {
+				b.WriteByte(token[j])
+				continue
+			}
+			if j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1') {
+				return nil, internal.NewConversionError(pointer, "JSON pointer", internal.ErrInvalidFormat)
+			}
+			if token[j+1] == '0' {
+				b.WriteByte('~')
+			} else {
+				b.WriteByte('/')
+			}
+			j++
+		}
+		tokens[i] = b.String()
+	}
+	return tokens, nil
+}
+
+// pointerIndex parses an array index token, which must not have leading zeros.
+// The "-" token, which references the element after the last, never exists.
+func pointerIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(token)
+	return index, err == nil
+}
+
+// queryNode returns value as a list when it is a slice or array other than []byte,
+// or as a map when it is a map or a struct. ok is false for other values.
+func queryNode(value interface{}) (list []interface{}, m map[string]interface{}, ok bool) {
+	if value == nil {
+		return nil, nil, false
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return nil, nil, false
+		}
+		list = make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = rv.Index(i).Interface()
+		}
+		return list, nil, true
+	case reflect.Map:
+		m, err := ToMapE(rv.Interface())
+		return nil, m, err == nil
+	case reflect.Struct:
+		m, err := structToMap(rv)
+		return nil, m, err == nil
+	}
+	return nil, nil, false
+}
+
+// queryChildren returns the elements of a list, or the values of a map ordered by key.
+func queryChildren(value interface{}) []interface{} {
+	list, m, ok := queryNode(value)
+	if !ok || list != nil {
+		return list
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	children := make([]interface{}, len(keys))
+	for i, k := range keys {
+		children[i] = m[k]
+	}
+	return children
+}
@@ -1,6 +1,7 @@
 package complex
 
 import (
+	"errors"
 	"reflect"
 
 	"github.com/graingo/mconv/basic"
@@ -14,7 +15,12 @@ func ToSlice(value interface{}) []interface{} {
 }
 
 // ToSliceE converts any type to []interface{} with error.
+// Slices, arrays and pointers to arrays are expanded element-wise. Other values,
+// including strings, channels and functions, are wrapped as a single element. Use
+// ToSliceWithSplitE to split strings and ToSliceFromSeqE to drain a channel or
+// iterate an iter.Seq.
 func ToSliceE(value interface{}) ([]interface{}, error) {
+	value, _ = internal.Unwrap(value)
 	if value == nil {
 		return nil, nil
 	}
@@ -64,19 +70,56 @@ func ToSliceE(value interface{}) ([]interface{}, error) {
 		return []interface{}{v}, nil
 	default:
 		rv := reflect.ValueOf(value)
-		if rv.Kind() != reflect.Slice {
-			return []interface{}{value}, nil
+		// Expand pointers to arrays.
+		if rv.Kind() == reflect.Ptr && rv.Type().Elem().Kind() == reflect.Array {
+			if rv.IsNil() {
+				return nil, nil
+			}
+			rv = rv.Elem()
 		}
 
-		sliceLen := rv.Len()
-		result := make([]interface{}, sliceLen)
-		for i := 0; i < sliceLen; i++ {
-			result[i] = rv.Index(i).Interface()
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			sliceLen := rv.Len()
+			result := make([]interface{}, sliceLen)
+			for i := 0; i < sliceLen; i++ {
+				result[i] = rv.Index(i).Interface()
+			}
+			return result, nil
 		}
-		return result, nil
+		return []interface{}{value}, nil
 	}
 }
 
+// ToSliceFromSeq collects the values of a receive channel or an iter.Seq-style function.
+func ToSliceFromSeq(value interface{}) []interface{} {
+	result, _ := ToSliceFromSeqE(value)
+	return result
+}
+
+// ToSliceFromSeqE collects the values of a receive channel or an iter.Seq-style
+// function func(yield func(V) bool) with error. Channels are received from until
+// they are closed, so an open channel blocks the call. It requires Go 1.23 or later
+// and returns an error when built with an older toolchain.
+func ToSliceFromSeqE(value interface{}) ([]interface{}, error) {
+	value, _ = internal.Unwrap(value)
+	if value == nil {
+		return nil, nil
+	}
+	if drainIterable == nil {
+		return nil, internal.NewConversionError(value, "slice", errors.New("draining requires Go 1.23 or later"))
+	}
+	result, ok := drainIterable(reflect.ValueOf(value))
+	if !ok {
+		return nil, internal.NewConversionError(value, "slice", internal.ErrUnsupportedType)
+	}
+	return result, nil
+}
+
+// drainIterable collects the values of channels and iter.Seq-style functions.
+// It is only available when built with Go 1.23 or later, see slice_iter.go.
+var drainIterable func(rv reflect.Value) ([]interface{}, bool)
+
 // ToStringSlice converts any type to []string
 func ToStringSlice(value interface{}) []string {
 	result, _ := ToStringSliceE(value)
@@ -85,6 +128,7 @@ func ToStringSlice(value interface{}) []string {
 
 // ToStringSliceE converts any type to []string with error
 func ToStringSliceE(value interface{}) ([]string, error) {
+	value, _ = internal.Unwrap(value)
 	if value == nil {
 		return nil, nil
 	}
@@ -135,6 +179,7 @@ func ToIntSlice(value interface{}) []int {
 
 // ToIntSliceE converts any type to []int with error.
 func ToIntSliceE(value interface{}) ([]int, error) {
+	value, _ = internal.Unwrap(value)
 	if value == nil {
 		return nil, nil
 	}
@@ -180,6 +225,7 @@ func ToFloat64Slice(value interface{}) []float64 {
 
 // ToFloat64SliceE converts any type to []float64 with error.
 func ToFloat64SliceE(value interface{}) ([]float64, error) {
+	value, _ = internal.Unwrap(value)
 	if value == nil {
 		return nil, nil
 	}
@@ -1,6 +1,7 @@
 package complex
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/graingo/mconv/basic"
@@ -29,6 +30,8 @@ func ToSliceT[T any](value interface{}) []T {
 // ToSliceTE converts any type to []T with error.
 // This is a generic version of ToSliceE that returns a slice of type T.
 // It uses reflection caching to improve performance for repeated conversions.
+// Each element is converted like a ToStructE field, so T may be any numeric type,
+// time.Time, a struct decoded from a map, or a nested slice, array or map.
 //
 // Examples:
 //
@@ -40,7 +43,11 @@ func ToSliceT[T any](value interface{}) []T {
 //
 //	// Convert to []float64 with error handling
 //	floatSlice, err := ToSliceTE[float64](value)
+//
+//	// Decode []map[string]interface{} into structs
+//	users, err := ToSliceTE[User](rows)
 func ToSliceTE[T any](value interface{}) ([]T, error) {
+	value, _ = internal.Unwrap(value)
 	if value == nil {
 		return nil, nil
 	}
@@ -106,16 +113,13 @@ func ToSliceTE[T any](value interface{}) ([]T, error) {
 			}
 			result[i] = any(boolVal).(T)
 		default:
-			// For other types, try direct conversion using cached type information
-			// Use cached assignability and convertibility checks for better performance
+			// Values that are already a T are used as-is, everything else goes through
+			// the same engine as ToStructE fields: basic converters, hooks, struct
+			// decoding and nested slices, arrays and maps.
 			if vTypeInfo.IsAssignableTo(targetType) {
 				result[i] = v.(T)
-			} else if vTypeInfo.IsConvertibleTo(targetType) {
-				// Try to convert using reflection
-				converted := vValue.Convert(targetType)
-				result[i] = converted.Interface().(T)
-			} else {
-				return nil, internal.NewConversionError(v, "T", internal.ErrConversionFailed)
+			} else if e := setFieldValue(reflect.ValueOf(&result[i]).Elem(), v, defaultHooks()...); e != nil {
+				return nil, internal.NewConversionError(v, "T", fmt.Errorf("element %d: %w", i, e))
 			}
 		}
 	}
@@ -0,0 +1,50 @@
+//go:build go1.23
+
+package complex
+
+import "reflect"
+
+func init() {
+	drainIterable = drainIterableSeq
+}
+
+// drainIterableSeq collects the values of a receive channel, blocking until it is closed,
+// or of a function with the iter.Seq signature func(yield func(V) bool).
+func drainIterableSeq(rv reflect.Value) ([]interface{}, bool) {
+	switch rv.Kind() {
+	case reflect.Chan:
+		if rv.Type().ChanDir()&reflect.RecvDir == 0 {
+			return nil, false
+		}
+		if rv.IsNil() {
+			return []interface{}{}, true
+		}
+	case reflect.Func:
+		if !isSeqFunc(rv.Type()) {
+			return nil, false
+		}
+		if rv.IsNil() {
+			return []interface{}{}, true
+		}
+	default:
+		return nil, false
+	}
+
+	result := make([]interface{}, 0)
+	for v := range rv.Seq() {
+		result = append(result, v.Interface())
+	}
+	return result, true
+}
+
+// isSeqFunc reports whether t has the shape of iter.Seq[V].
+func isSeqFunc(t reflect.Type) bool {
+	if t.NumIn() != 1 || t.NumOut() != 0 {
+		return false
+	}
+	yield := t.In(0)
+	return yield.Kind() == reflect.Func &&
+		yield.NumIn() == 1 &&
+		yield.NumOut() == 1 &&
+		yield.Out(0).Kind() == reflect.Bool
+}
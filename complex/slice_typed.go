@@ -0,0 +1,126 @@
+package complex
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/graingo/mconv/basic"
+	"github.com/graingo/mconv/internal"
+)
+
+// ToInt64Slice converts any type to []int64.
+func ToInt64Slice(value interface{}) []int64 {
+	result, _ := ToInt64SliceE(value)
+	return result
+}
+
+// ToInt64SliceE converts any type to []int64 with error.
+func ToInt64SliceE(value interface{}) ([]int64, error) {
+	return toTypedSliceE(value, "[]int64", basic.ToInt64E)
+}
+
+// ToInt32Slice converts any type to []int32.
+func ToInt32Slice(value interface{}) []int32 {
+	result, _ := ToInt32SliceE(value)
+	return result
+}
+
+// ToInt32SliceE converts any type to []int32 with error.
+func ToInt32SliceE(value interface{}) ([]int32, error) {
+	return toTypedSliceE(value, "[]int32", basic.ToInt32E)
+}
+
+// ToUintSlice converts any type to []uint.
+func ToUintSlice(value interface{}) []uint {
+	result, _ := ToUintSliceE(value)
+	return result
+}
+
+// ToUintSliceE converts any type to []uint with error.
+func ToUintSliceE(value interface{}) ([]uint, error) {
+	return toTypedSliceE(value, "[]uint", basic.ToUintE)
+}
+
+// ToUint64Slice converts any type to []uint64.
+func ToUint64Slice(value interface{}) []uint64 {
+	result, _ := ToUint64SliceE(value)
+	return result
+}
+
+// ToUint64SliceE converts any type to []uint64 with error.
+func ToUint64SliceE(value interface{}) ([]uint64, error) {
+	return toTypedSliceE(value, "[]uint64", basic.ToUint64E)
+}
+
+// ToFloat32Slice converts any type to []float32.
+func ToFloat32Slice(value interface{}) []float32 {
+	result, _ := ToFloat32SliceE(value)
+	return result
+}
+
+// ToFloat32SliceE converts any type to []float32 with error.
+func ToFloat32SliceE(value interface{}) ([]float32, error) {
+	return toTypedSliceE(value, "[]float32", basic.ToFloat32E)
+}
+
+// ToBoolSlice converts any type to []bool.
+func ToBoolSlice(value interface{}) []bool {
+	result, _ := ToBoolSliceE(value)
+	return result
+}
+
+// ToBoolSliceE converts any type to []bool with error.
+func ToBoolSliceE(value interface{}) ([]bool, error) {
+	return toTypedSliceE(value, "[]bool", basic.ToBoolE)
+}
+
+// ToTimeSlice converts any type to []time.Time.
+func ToTimeSlice(value interface{}, formats ...string) []time.Time {
+	result, _ := ToTimeSliceE(value, formats...)
+	return result
+}
+
+// ToTimeSliceE converts any type to []time.Time with error.
+// Optional formats are passed to ToTimeE for every element.
+func ToTimeSliceE(value interface{}, formats ...string) ([]time.Time, error) {
+	return toTypedSliceE(value, "[]time.Time", func(v interface{}) (time.Time, error) {
+		return basic.ToTimeE(v, formats...)
+	})
+}
+
+// ToDurationSlice converts any type to []time.Duration.
+func ToDurationSlice(value interface{}) []time.Duration {
+	result, _ := ToDurationSliceE(value)
+	return result
+}
+
+// ToDurationSliceE converts any type to []time.Duration with error.
+func ToDurationSliceE(value interface{}) ([]time.Duration, error) {
+	return toTypedSliceE(value, "[]time.Duration", basic.ToDurationE)
+}
+
+// toTypedSliceE converts value to a slice with ToSliceE and each element with convert.
+// Errors report the index of the element that failed.
+func toTypedSliceE[T any](value interface{}, targetType string, convert func(interface{}) (T, error)) ([]T, error) {
+	value, _ = internal.Unwrap(value)
+	if value == nil {
+		return nil, nil
+	}
+	if v, ok := value.([]T); ok {
+		return v, nil
+	}
+
+	slice, err := ToSliceE(value)
+	if err != nil {
+		return nil, internal.NewConversionError(value, targetType, err)
+	}
+	result := make([]T, len(slice))
+	for i, val := range slice {
+		converted, err := convert(val)
+		if err != nil {
+			return nil, internal.NewConversionError(val, targetType, fmt.Errorf("element %d: %w", i, err))
+		}
+		result[i] = converted
+	}
+	return result, nil
+}
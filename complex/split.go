@@ -0,0 +1,217 @@
+package complex
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/graingo/mconv/basic"
+	"github.com/graingo/mconv/internal"
+)
+
+// SplitOptions controls how strings are split into slice elements.
+type SplitOptions struct {
+	// Separators lists the runes that separate elements. A whitespace rune
+	// matches any run of whitespace, so " " splits on spaces, tabs and newlines.
+	Separators string
+	// TrimSpace removes whitespace around unquoted element text.
+	TrimSpace bool
+	// Quotes keeps separators inside '...' or "..." as part of the element.
+	Quotes bool
+	// Escapes makes a backslash take the next rune literally.
+	Escapes bool
+	// JSONArrays decodes strings such as "[1,2,3]" as JSON arrays.
+	// Strings that look like arrays but are not valid JSON are split as usual.
+	JSONArrays bool
+	// OmitEmpty drops empty elements, such as the middle of "a,,b".
+	OmitEmpty bool
+}
+
+// DefaultSplitOptions returns comma-separated splitting with trimming, quoting,
+// escapes and JSON array detection enabled.
+func DefaultSplitOptions() SplitOptions {
+	return SplitOptions{
+		Separators: ",",
+		TrimSpace:  true,
+		Quotes:     true,
+		Escapes:    true,
+		JSONArrays: true,
+	}
+}
+
+// ToSliceWithSplit converts any type to []interface{}, splitting strings according to opts.
+func ToSliceWithSplit(value interface{}, opts SplitOptions) []interface{} {
+	result, _ := ToSliceWithSplitE(value, opts)
+	return result
+}
+
+// ToSliceWithSplitE converts any type to []interface{} like ToSliceE, but splits
+// strings according to opts, with error.
+// The result can be passed to the typed slice converters, for example:
+//
+//	ports, err := ToSliceTE[int](ToSliceWithSplit("80, 443", DefaultSplitOptions()))
+func ToSliceWithSplitE(value interface{}, opts SplitOptions) ([]interface{}, error) {
+	value, _ = internal.Unwrap(value)
+	if s, ok := value.(string); ok {
+		return splitStringValue(s, opts)
+	}
+	return ToSliceE(value)
+}
+
+// SplitHook returns a HookFunc that splits strings according to opts when they are
+// converted into slice or array fields by ToStructE. Byte slices are left alone.
+//
+//	err := ToStructE(env, &cfg, SplitHook(DefaultSplitOptions()))
+func SplitHook(opts SplitOptions) HookFunc {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from == nil || from.Kind() != reflect.String {
+			return data, nil
+		}
+		if (to.Kind() != reflect.Slice && to.Kind() != reflect.Array) || to.Elem().Kind() == reflect.Uint8 {
+			return data, nil
+		}
+		return splitStringValue(reflect.ValueOf(data).String(), opts)
+	}
+}
+
+// SplitString splits s into elements according to opts.
+func SplitString(s string, opts SplitOptions) ([]string, error) {
+	values, err := splitStringValue(s, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, len(values))
+	for i, v := range values {
+		str, err := basic.ToStringE(v)
+		if err != nil {
+			return nil, internal.NewConversionError(s, "[]string", err)
+		}
+		result[i] = str
+	}
+	return result, nil
+}
+
+// splitStringValue splits s into elements. Elements decoded from a JSON array keep
+// their JSON types, with numbers represented as json.Number.
+func splitStringValue(s string, opts SplitOptions) ([]interface{}, error) {
+	if opts.JSONArrays {
+		if values, ok := decodeJSONArray(s); ok {
+			return values, nil
+		}
+	}
+
+	fields, err := splitFields(s, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, len(fields))
+	for i, f := range fields {
+		result[i] = f
+	}
+	return result, nil
+}
+
+// decodeJSONArray decodes s if it is a valid JSON array.
+func decodeJSONArray(s string) ([]interface{}, bool) {
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "[") || !strings.HasSuffix(trimmed, "]") {
+		return nil, false
+	}
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var values []interface{}
+	if err := decoder.Decode(&values); err != nil || decoder.More() {
+		return nil, false
+	}
+	if values == nil {
+		values = []interface{}{}
+	}
+	return values, true
+}
+
+// splitFields splits s on the configured separators, honoring quotes and escapes.
+func splitFields(s string, opts SplitOptions) ([]string, error) {
+	spaceSep := strings.IndexFunc(opts.Separators, unicode.IsSpace) >= 0
+	isSep := func(r rune) bool {
+		return strings.ContainsRune(opts.Separators, r) || (spaceSep && unicode.IsSpace(r))
+	}
+
+	fields := make([]string, 0)
+	var (
+		cur       strings.Builder
+		protected int  // bytes of cur that must not be trimmed
+		quoted    bool // the current field contains a quoted section
+		quote     rune // the open quote rune, or 0
+		escaped   bool
+		afterSpc  bool // the previous field was ended by whitespace
+	)
+
+	emit := func() {
+		f := cur.String()
+		if opts.TrimSpace {
+			f = f[:protected] + strings.TrimRightFunc(f[protected:], unicode.IsSpace)
+		}
+		if !opts.OmitEmpty || f != "" {
+			fields = append(fields, f)
+		}
+		cur.Reset()
+		protected = 0
+		quoted = false
+	}
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			protected = cur.Len()
+			escaped = false
+			continue
+		case opts.Escapes && r == '\\':
+			escaped = true
+			continue
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+			protected = cur.Len()
+			continue
+		case opts.Quotes && (r == '"' || r == '\''):
+			quote = r
+			quoted = true
+			continue
+		}
+
+		empty := cur.Len() == 0 && !quoted
+		if isSep(r) {
+			if spaceSep && unicode.IsSpace(r) {
+				// Runs of whitespace act as a single separator.
+				if !empty {
+					emit()
+					afterSpc = true
+				}
+				continue
+			}
+			if !(empty && afterSpc) {
+				emit()
+			}
+			afterSpc = false
+			continue
+		}
+		if opts.TrimSpace && empty && unicode.IsSpace(r) {
+			continue
+		}
+		cur.WriteRune(r)
+		afterSpc = false
+	}
+
+	if quote != 0 || escaped {
+		return nil, internal.NewConversionError(s, "slice", internal.ErrInvalidFormat)
+	}
+	if cur.Len() > 0 || quoted || (len(fields) > 0 && !afterSpc) {
+		emit()
+	}
+	return fields, nil
+}
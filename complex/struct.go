@@ -1,9 +1,12 @@
 package complex
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/graingo/mconv/basic"
@@ -20,7 +23,10 @@ type HookFunc func(from reflect.Type, to reflect.Type, data interface{}) (interf
 
 // ToStruct converts a map or a struct to a struct.
 // The `pointer` parameter should be a pointer to a struct.
-// It supports `mconv` tag for custom field mapping.
+// It supports `mconv` tag for custom field mapping. An mconv tag may be a path such as
+// "meta.author.name" or "items[0].id" to read from nested source data; escape literal
+// dots with a backslash. Fields without an mconv tag fall back to their json, yaml,
+// toml and ini tags, in that order.
 // It accepts optional HookFuncs to provide custom conversion logic.
 func ToStruct(source, pointer interface{}, hooks ...HookFunc) {
 	_ = ToStructE(source, pointer, hooks...)
@@ -31,10 +37,10 @@ func ToStructE(source, pointer interface{}, hooks ...HookFunc) error {
 	if pointer == nil {
 		return errors.New("pointer cannot be nil")
 	}
+	source, _ = internal.Unwrap(source)
 
 	// Prepend default hooks
-	allHooks := []HookFunc{stringToTimeHookFunc(), stringToDurationHookFunc(), intToBoolHookFunc()}
-	allHooks = append(allHooks, hooks...)
+	allHooks := append(defaultHooks(), hooks...)
 
 	// Get the reflect.Value of the pointer and the struct
 	pointerRv := reflect.ValueOf(pointer)
@@ -85,6 +91,11 @@ func ToStructE(source, pointer interface{}, hooks ...HookFunc) error {
 			}
 		}
 
+		// 3. Resolve dotted paths against nested source data.
+		if !ok && fieldDecoder.Path != nil {
+			mapValue, ok = lookupPath(sourceMap, fieldDecoder.Path)
+		}
+
 		if !ok {
 			continue
 		}
@@ -146,6 +157,30 @@ func setFieldValue(field reflect.Value, value interface{}, hooks ...HookFunc) er
 		return nil
 	}
 
+	// Unwrap wrappers such as *Var that are not themselves the field type.
+	if inner, ok := internal.Unwrap(value); ok {
+		return setFieldValue(field, inner, hooks...)
+	}
+
+	// Unwrap driver.Valuer sources such as sql.NullString; invalid values leave the field untouched.
+	if valuer, ok := value.(driver.Valuer); ok {
+		if valueRv.Kind() == reflect.Ptr && valueRv.IsNil() {
+			return nil
+		}
+		dv, err := valuer.Value()
+		if err != nil {
+			return err
+		}
+		if dv == nil || reflect.TypeOf(dv) != valueRv.Type() {
+			return setFieldValue(field, dv, hooks...)
+		}
+	}
+
+	// Handle database/sql Null types and other sql.Scanner destinations.
+	if handled, err := setScannerValue(field, value, hooks...); handled {
+		return err
+	}
+
 	// Handle pointer fields
 	if field.Kind() == reflect.Ptr {
 		if !valueRv.IsValid() {
@@ -215,6 +250,22 @@ func setFieldValue(field reflect.Value, value interface{}, hooks ...HookFunc) er
 			}
 		}
 		field.Set(newSlice)
+	case reflect.Array:
+		sliceData, err := ToSliceE(value)
+		if err != nil {
+			return err
+		}
+		if len(sliceData) > field.Len() {
+			return fmt.Errorf("cannot fit %d elements into array of type %s", len(sliceData), field.Type())
+		}
+		// Elements missing from the source keep their zero value.
+		newArray := reflect.New(field.Type()).Elem()
+		for i, v := range sliceData {
+			if err := setFieldValue(newArray.Index(i), v, hooks...); err != nil {
+				return err
+			}
+		}
+		field.Set(newArray)
 	case reflect.Map:
 		mapData, err := ToMapE(value)
 		if err != nil {
@@ -247,6 +298,45 @@ func setFieldValue(field reflect.Value, value interface{}, hooks ...HookFunc) er
 	return nil
 }
 
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// setScannerValue fills struct fields such as sql.NullString or sql.Null[T] from a scalar value.
+// Null types from database/sql are filled by converting the value into their first field
+// and marking them Valid; other sql.Scanner implementations are given the value to Scan.
+// It reports whether the field was handled.
+func setScannerValue(field reflect.Value, value interface{}, hooks ...HookFunc) (bool, error) {
+	t := field.Type()
+	if t.Kind() != reflect.Struct {
+		return false, nil
+	}
+	// Maps are decoded field by field as usual.
+	if reflect.TypeOf(value).Kind() == reflect.Map {
+		return false, nil
+	}
+
+	if isSQLNullType(t) {
+		if err := setFieldValue(field.Field(0), value, hooks...); err != nil {
+			return true, err
+		}
+		field.Field(1).SetBool(true)
+		return true, nil
+	}
+
+	if field.CanAddr() && reflect.PtrTo(t).Implements(scannerType) {
+		return true, field.Addr().Interface().(sql.Scanner).Scan(value)
+	}
+	return false, nil
+}
+
+// isSQLNullType reports whether t is one of the database/sql Null types,
+// which hold a value in their first field and a Valid flag in their second.
+func isSQLNullType(t reflect.Type) bool {
+	return t.PkgPath() == "database/sql" &&
+		t.NumField() == 2 &&
+		t.Field(1).Name == "Valid" &&
+		t.Field(1).Type.Kind() == reflect.Bool
+}
+
 // getDecoder retrieves a decoder for a given struct type from the cache.
 // If the decoder is not found in the cache, it builds a new one, caches it, and returns it.
 func getDecoder(destType reflect.Type, hooks ...HookFunc) (*internal.Decoder, error) {
@@ -286,12 +376,19 @@ func buildDecoderFields(t reflect.Type, indexPrefix []int, decoder *internal.Dec
 
 		// Parse the tag.
 		tag := field.Tag.Get("mconv")
+		isMconvTag := tag != ""
 		if tag == "" {
 			tag = field.Tag.Get("json")
 		}
 		if tag == "" {
 			tag = field.Tag.Get("yaml")
 		}
+		if tag == "" {
+			tag = field.Tag.Get("toml")
+		}
+		if tag == "" {
+			tag = field.Tag.Get("ini")
+		}
 
 		if tag == "-" {
 			continue
@@ -303,6 +400,15 @@ func buildDecoderFields(t reflect.Type, indexPrefix []int, decoder *internal.Dec
 			key = parts[0]
 		}
 
+		// Only mconv tags are paths; keys from other tags may legitimately contain dots.
+		var path []internal.PathSegment
+		if isMconvTag {
+			path = parseTagPath(key)
+			if len(path) == 1 && !path[0].IsIndex {
+				key, path = path[0].Key, nil
+			}
+		}
+
 		// If a field with the same name already exists in a shallower layer, skip this one.
 		if _, ok := decoder.Fields[key]; ok {
 			continue
@@ -312,6 +418,7 @@ func buildDecoderFields(t reflect.Type, indexPrefix []int, decoder *internal.Dec
 			Field: field,
 			Index: append(append([]int(nil), indexPrefix...), i), // Must be a copy
 			Name:  key,
+			Path:  path,
 		}
 
 		decoder.FieldArr = append(decoder.FieldArr, fieldDecoder)
@@ -319,6 +426,132 @@ func buildDecoderFields(t reflect.Type, indexPrefix []int, decoder *internal.Dec
 	}
 }
 
+// parseTagPath splits a tag such as "meta.items[0].id" into path segments.
+// A backslash escapes the next character, so `a\.b` is the single key "a.b".
+// It returns nil if the tag is not a well-formed path.
+func parseTagPath(tag string) []internal.PathSegment {
+	var (
+		path    []internal.PathSegment
+		key     strings.Builder
+		escaped bool
+		// closed is set after an index so that a following "." does not add an empty key.
+		closed bool
+	)
+	runes := []rune(tag)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case escaped:
+			key.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '.':
+			if key.Len() == 0 && !closed {
+				return nil
+			}
+			if key.Len() > 0 {
+				path = append(path, internal.PathSegment{Key: key.String()})
+				key.Reset()
+			}
+			closed = false
+		case r == '[':
+			if key.Len() > 0 {
+				path = append(path, internal.PathSegment{Key: key.String()})
+				key.Reset()
+			} else if !closed {
+				return nil
+			}
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil
+			}
+			index, err := strconv.Atoi(string(runes[i+1 : end]))
+			if err != nil || index < 0 {
+				return nil
+			}
+			path = append(path, internal.PathSegment{Index: index, IsIndex: true})
+			i = end
+			closed = true
+		default:
+			if closed {
+				return nil
+			}
+			key.WriteRune(r)
+		}
+	}
+	if escaped {
+		return nil
+	}
+	if key.Len() > 0 {
+		path = append(path, internal.PathSegment{Key: key.String()})
+	} else if !closed {
+		return nil
+	}
+	return path
+}
+
+// lookupPath resolves a path against nested maps, structs, slices and arrays.
+// Map keys are matched case-sensitively first, then case-insensitively, and
+// numeric keys index into slices, so "items.0" is the same as "items[0]".
+func lookupPath(data interface{}, path []internal.PathSegment) (interface{}, bool) {
+	current := data
+	for _, seg := range path {
+		if current == nil {
+			return nil, false
+		}
+		rv := reflect.ValueOf(current)
+		if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			index, ok := segmentIndex(seg)
+			if !ok || index >= rv.Len() {
+				return nil, false
+			}
+			current = rv.Index(index).Interface()
+			continue
+		}
+		if seg.IsIndex {
+			return nil, false
+		}
+
+		m, err := ToMapE(current)
+		if err != nil {
+			return nil, false
+		}
+		next, ok := m[seg.Key]
+		if !ok {
+			for k, v := range m {
+				if strings.EqualFold(k, seg.Key) {
+					next, ok = v, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// segmentIndex returns the slice index of a path segment, accepting numeric keys.
+func segmentIndex(seg internal.PathSegment) (int, bool) {
+	if seg.IsIndex {
+		return seg.Index, true
+	}
+	index, err := strconv.Atoi(seg.Key)
+	if err != nil || index < 0 || strconv.Itoa(index) != seg.Key {
+		return 0, false
+	}
+	return index, true
+}
+
 // isUnexportedField checks if a struct field is unexported.
 func isUnexportedField(field reflect.StructField) bool {
 	return field.PkgPath != ""
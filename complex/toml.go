@@ -0,0 +1,775 @@
+package complex
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// TOMLError describes invalid TOML input. It matches internal.ErrInvalidTOMLFormat
+// with errors.Is and unwraps to the underlying error.
+type TOMLError struct {
+	// Err describes the problem.
+	Err error
+	// Line and Column are the 1-based position of the problem in the input.
+	Line   int
+	Column int
+}
+
+// Error implements the error interface.
+func (e *TOMLError) Error() string {
+	return fmt.Sprintf("%v at line %d, column %d: %v", internal.ErrInvalidTOMLFormat, e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TOMLError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is internal.ErrInvalidTOMLFormat.
+func (e *TOMLError) Is(target error) bool {
+	return target == internal.ErrInvalidTOMLFormat
+}
+
+// ToMapFromTOMLE decodes a TOML 1.0 document into map[string]interface{} with error.
+//
+// Tables and inline tables become map[string]interface{}, arrays of tables and arrays
+// become []interface{}, and strings, integers (int64), floats (float64) and booleans
+// keep their types. Offset date-times become time.Time in their own offset; local
+// date-times, local dates (at midnight) and local times (on January 1 of year 0)
+// become time.Time in time.Local. An empty document yields a nil map.
+func ToMapFromTOMLE(tomlStr string) (map[string]interface{}, error) {
+	if tomlStr == "" {
+		return nil, nil
+	}
+	p := &tomlParser{src: strings.TrimPrefix(tomlStr, "\ufeff")}
+	root, err := p.parse()
+	if err != nil {
+		return nil, internal.NewConversionError(tomlStr, "map", err)
+	}
+	return root.toMap(), nil
+}
+
+// ToMapFromTOML decodes a TOML 1.0 document into map[string]interface{}.
+func ToMapFromTOML(tomlStr string) map[string]interface{} {
+	result, _ := ToMapFromTOMLE(tomlStr)
+	return result
+}
+
+// tomlTable is a table under construction. TOML forbids defining a table twice, so
+// it records how the table was defined.
+type tomlTable struct {
+	entries map[string]interface{}
+	// explicit is set for tables defined by a [header], dotted for tables defined by
+	// dotted keys and inline for inline tables, which cannot be extended at all.
+	explicit, dotted, inline bool
+}
+
+// tomlTableArray is an array of tables built by [[header]] entries.
+type tomlTableArray struct {
+	tables []*tomlTable
+}
+
+func newTOMLTable() *tomlTable {
+	return &tomlTable{entries: make(map[string]interface{})}
+}
+
+// toMap converts the table and everything in it into plain maps and slices.
+func (t *tomlTable) toMap() map[string]interface{} {
+	result := make(map[string]interface{}, len(t.entries))
+	for k, v := range t.entries {
+		result[k] = tomlValue(v)
+	}
+	return result
+}
+
+func tomlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *tomlTable:
+		return v.toMap()
+	case *tomlTableArray:
+		list := make([]interface{}, len(v.tables))
+		for i, table := range v.tables {
+			list[i] = table.toMap()
+		}
+		return list
+	case []interface{}:
+		for i := range v {
+			v[i] = tomlValue(v[i])
+		}
+		return v
+	}
+	return value
+}
+
+// tomlParser is a recursive-descent parser for TOML 1.0.
+type tomlParser struct {
+	src string
+	pos int
+}
+
+// errorAt reports a syntax error at offset pos.
+func (p *tomlParser) errorAt(pos int, format string, args ...interface{}) error {
+	if pos > len(p.src) {
+		pos = len(p.src)
+	}
+	line := 1 + strings.Count(p.src[:pos], "\n")
+	column := pos - strings.LastIndexByte(p.src[:pos], '\n')
+	return &TOMLError{Err: fmt.Errorf(format, args...), Line: line, Column: column}
+}
+
+// errorf reports a syntax error at the current position.
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+// peekAt returns the byte at offset pos, or 0 at the end of the input.
+func (p *tomlParser) peekAt(pos int) byte {
+	if pos < len(p.src) {
+		return p.src[pos]
+	}
+	return 0
+}
+
+// parse parses the whole document into the root table.
+func (p *tomlParser) parse() (*tomlTable, error) {
+	root := newTOMLTable()
+	current := root
+	for {
+		p.skipBlankLines()
+		if p.pos >= len(p.src) {
+			return root, nil
+		}
+		var err error
+		if p.src[p.pos] == '[' {
+			current, err = p.parseHeader(root)
+		} else {
+			err = p.parseKeyValue(current)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := p.endLine(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// skipWhitespace skips spaces and tabs.
+func (p *tomlParser) skipWhitespace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// skipComment skips a comment up to the end of its line.
+func (p *tomlParser) skipComment() {
+	if p.peekAt(p.pos) != '#' {
+		return
+	}
+	if i := strings.IndexByte(p.src[p.pos:], '\n'); i >= 0 {
+		p.pos += i
+	} else {
+		p.pos = len(p.src)
+	}
+}
+
+// skipNewline skips a line break, reporting whether there was one.
+func (p *tomlParser) skipNewline() bool {
+	switch {
+	case p.peekAt(p.pos) == '\n':
+		p.pos++
+	case p.peekAt(p.pos) == '\r' && p.peekAt(p.pos+1) == '\n':
+		p.pos += 2
+	default:
+		return false
+	}
+	return true
+}
+
+// skipBlankLines skips whitespace, comments and line breaks.
+func (p *tomlParser) skipBlankLines() {
+	for {
+		p.skipWhitespace()
+		p.skipComment()
+		if !p.skipNewline() {
+			return
+		}
+	}
+}
+
+// endLine checks that only whitespace or a comment follows on the current line.
+func (p *tomlParser) endLine() error {
+	p.skipWhitespace()
+	p.skipComment()
+	if p.pos < len(p.src) && !p.skipNewline() {
+		return p.errorf("expected a line break, but got %q", p.src[p.pos])
+	}
+	return nil
+}
+
+// parseHeader parses a [table] or [[array of tables]] header and returns the table
+// that the following key/value pairs belong to.
+func (p *tomlParser) parseHeader(root *tomlTable) (*tomlTable, error) {
+	start := p.pos
+	array := strings.HasPrefix(p.src[p.pos:], "[[")
+	closer := "]"
+	if array {
+		closer = "]]"
+		p.pos++
+	}
+	p.pos++
+	keys, err := p.parseKey()
+	if err != nil {
+		return nil, err
+	}
+	p.skipWhitespace()
+	if !strings.HasPrefix(p.src[p.pos:], closer) {
+		return nil, p.errorf("expected %q after table name", closer)
+	}
+	p.pos += len(closer)
+
+	name := strings.Join(keys, ".")
+	table := root
+	for _, key := range keys[:len(keys)-1] {
+		switch v := table.entries[key].(type) {
+		case nil:
+			next := newTOMLTable()
+			table.entries[key] = next
+			table = next
+		case *tomlTable:
+			if v.inline {
+				return nil, p.errorAt(start, "cannot extend inline table %q", key)
+			}
+			table = v
+		case *tomlTableArray:
+			table = v.tables[len(v.tables)-1]
+		default:
+			return nil, p.errorAt(start, "key %q is already defined as a value", key)
+		}
+	}
+
+	last := keys[len(keys)-1]
+	if array {
+		existing := table.entries[last]
+		list, ok := existing.(*tomlTableArray)
+		if existing != nil && !ok {
+			return nil, p.errorAt(start, "key %q is already defined", name)
+		}
+		if list == nil {
+			list = &tomlTableArray{}
+			table.entries[last] = list
+		}
+		next := newTOMLTable()
+		next.explicit = true
+		list.tables = append(list.tables, next)
+		return next, nil
+	}
+	switch v := table.entries[last].(type) {
+	case nil:
+		next := newTOMLTable()
+		next.explicit = true
+		table.entries[last] = next
+		return next, nil
+	case *tomlTable:
+		if v.explicit || v.dotted || v.inline {
+			return nil, p.errorAt(start, "table %q is already defined", name)
+		}
+		v.explicit = true
+		return v, nil
+	}
+	return nil, p.errorAt(start, "key %q is already defined", name)
+}
+
+// parseKeyValue parses a key = value pair into table.
+func (p *tomlParser) parseKeyValue(table *tomlTable) error {
+	start := p.pos
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipWhitespace()
+	if p.peekAt(p.pos) != '=' {
+		return p.errorf("expected '=' after key")
+	}
+	p.pos++
+	p.skipWhitespace()
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	return p.setValue(table, keys, value, start)
+}
+
+// setValue stores value under a possibly dotted key, creating the intermediate tables.
+func (p *tomlParser) setValue(table *tomlTable, keys []string, value interface{}, pos int) error {
+	for i, key := range keys[:len(keys)-1] {
+		switch v := table.entries[key].(type) {
+		case nil:
+			next := newTOMLTable()
+			next.dotted = true
+			table.entries[key] = next
+			table = next
+		case *tomlTable:
+			if v.explicit || v.inline {
+				return p.errorAt(pos, "cannot add keys to table %q with dotted keys", strings.Join(keys[:i+1], "."))
+			}
+			v.dotted = true
+			table = v
+		default:
+			return p.errorAt(pos, "key %q is already defined", strings.Join(keys[:i+1], "."))
+		}
+	}
+	last := keys[len(keys)-1]
+	if _, exists := table.entries[last]; exists {
+		return p.errorAt(pos, "duplicate key %q", strings.Join(keys, "."))
+	}
+	table.entries[last] = value
+	return nil
+}
+
+// parseKey parses a bare, quoted or dotted key.
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipWhitespace()
+		start := p.pos
+		var (
+			key string
+			err error
+		)
+		switch c := p.peekAt(p.pos); {
+		case c == '"':
+			if strings.HasPrefix(p.src[p.pos:], `"""`) {
+				return nil, p.errorf("multi-line strings cannot be keys")
+			}
+			key, err = p.parseBasicString()
+		case c == '\'':
+			if strings.HasPrefix(p.src[p.pos:], "'''") {
+				return nil, p.errorf("multi-line strings cannot be keys")
+			}
+			key, err = p.parseLiteralString()
+		case isTOMLBareKeyChar(c):
+			for isTOMLBareKeyChar(p.peekAt(p.pos)) {
+				p.pos++
+			}
+			key = p.src[start:p.pos]
+		default:
+			return nil, p.errorf("expected a key")
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		p.skipWhitespace()
+		if p.peekAt(p.pos) != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+// isTOMLBareKeyChar reports whether c may appear in a bare key.
+func isTOMLBareKeyChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == '-'
+}
+
+// parseValue parses the value at the current position.
+func (p *tomlParser) parseValue() (interface{}, error) {
+	switch p.peekAt(p.pos) {
+	case '"':
+		if strings.HasPrefix(p.src[p.pos:], `"""`) {
+			return p.parseMultiLineString('"')
+		}
+		return p.parseBasicString()
+	case '\'':
+		if strings.HasPrefix(p.src[p.pos:], "'''") {
+			return p.parseMultiLineString('\'')
+		}
+		return p.parseLiteralString()
+	case '[':
+		return p.parseArray()
+	case '{':
+		return p.parseInlineTable()
+	}
+
+	start := p.pos
+	for isTOMLValueChar(p.peekAt(p.pos)) {
+		p.pos++
+	}
+	// A date and a time may be separated by a space instead of a T.
+	if p.pos-start == 10 && isTOMLDate(p.src[start:p.pos]) && p.peekAt(p.pos) == ' ' && isTOMLTime(p.src[p.pos+1:]) {
+		for p.pos++; isTOMLValueChar(p.peekAt(p.pos)); p.pos++ {
+		}
+	}
+	token := p.src[start:p.pos]
+	switch {
+	case token == "":
+		return nil, p.errorf("expected a value")
+	case token == "true":
+		return true, nil
+	case token == "false":
+		return false, nil
+	case len(token) >= 10 && isTOMLDate(token[:10]):
+		if t, ok := parseTOMLDateTime(token); ok {
+			return t, nil
+		}
+		return nil, p.errorAt(start, "invalid date-time %q", token)
+	case isTOMLTime(token):
+		if t, err := time.ParseInLocation("15:04:05", token, time.Local); err == nil {
+			return t, nil
+		}
+		return nil, p.errorAt(start, "invalid time %q", token)
+	}
+	if n, ok := parseTOMLNumber(token); ok {
+		return n, nil
+	}
+	return nil, p.errorAt(start, "invalid value %q", token)
+}
+
+// isTOMLValueChar reports whether c may appear in a number, boolean or date-time.
+func isTOMLValueChar(c byte) bool {
+	return isTOMLBareKeyChar(c) || c == '+' || c == '.' || c == ':'
+}
+
+// isTOMLDate reports whether s starts like a date, 1979-05-27.
+func isTOMLDate(s string) bool {
+	if len(s) < 10 || s[4] != '-' || s[7] != '-' {
+		return false
+	}
+	for _, i := range []int{0, 1, 2, 3, 5, 6, 8, 9} {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isTOMLTime reports whether s starts like a time, 07:32.
+func isTOMLTime(s string) bool {
+	return len(s) >= 3 && s[0] >= '0' && s[0] <= '9' && s[1] >= '0' && s[1] <= '9' && s[2] == ':'
+}
+
+// parseTOMLDateTime parses an offset date-time, a local date-time or a local date.
+func parseTOMLDateTime(s string) (time.Time, bool) {
+	if len(s) == 10 {
+		t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+		return t, err == nil
+	}
+	if sep := s[10]; sep != 'T' && sep != 't' && sep != ' ' {
+		return time.Time{}, false
+	}
+	s = s[:10] + "T" + s[11:]
+	if strings.HasSuffix(s, "z") {
+		s = s[:len(s)-1] + "Z"
+	}
+	if strings.HasSuffix(s, "Z") || strings.ContainsAny(s[11:], "+-") {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return t, err == nil
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", s, time.Local)
+	return t, err == nil
+}
+
+// parseTOMLNumber parses an integer into int64 or a float into float64.
+func parseTOMLNumber(s string) (interface{}, bool) {
+	switch s {
+	case "inf", "+inf":
+		return math.Inf(1), true
+	case "-inf":
+		return math.Inf(-1), true
+	case "nan", "+nan", "-nan":
+		return math.NaN(), true
+	}
+	if len(s) > 2 && s[0] == '0' {
+		base := 0
+		switch s[1] {
+		case 'x':
+			base = 16
+		case 'o':
+			base = 8
+		case 'b':
+			base = 2
+		}
+		if base != 0 {
+			if !isTOMLDigits(s[2:], base) {
+				return nil, false
+			}
+			n, err := strconv.ParseInt(strings.ReplaceAll(s[2:], "_", ""), base, 64)
+			return n, err == nil
+		}
+	}
+
+	body := s
+	if body != "" && (body[0] == '+' || body[0] == '-') {
+		body = body[1:]
+	}
+	end := strings.IndexAny(body, ".eE")
+	if end < 0 {
+		end = len(body)
+	}
+	if !isTOMLDigits(body[:end], 10) || (end > 1 && body[0] == '0') {
+		return nil, false
+	}
+	if end == len(body) {
+		n, err := strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 10, 64)
+		return n, err == nil
+	}
+	rest := body[end:]
+	if rest[0] == '.' {
+		fraction := rest[1:]
+		if i := strings.IndexAny(fraction, "eE"); i >= 0 {
+			fraction, rest = fraction[:i], fraction[i:]
+		} else {
+			rest = ""
+		}
+		if !isTOMLDigits(fraction, 10) {
+			return nil, false
+		}
+	}
+	if rest != "" {
+		exponent := rest[1:]
+		if exponent != "" && (exponent[0] == '+' || exponent[0] == '-') {
+			exponent = exponent[1:]
+		}
+		if !isTOMLDigits(exponent, 10) {
+			return nil, false
+		}
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(s, "_", ""), 64)
+	return f, err == nil
+}
+
+// isTOMLDigits reports whether s is a non-empty run of digits in base, in which each
+// underscore sits between two digits.
+func isTOMLDigits(s string, base int) bool {
+	if s == "" || s[0] == '_' || s[len(s)-1] == '_' || strings.Contains(s, "__") {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c != '_' && !isTOMLDigit(c, base) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTOMLDigit(c byte, base int) bool {
+	switch base {
+	case 2:
+		return c == '0' || c == '1'
+	case 8:
+		return c >= '0' && c <= '7'
+	case 16:
+		return isHexDigit(c)
+	}
+	return c >= '0' && c <= '9'
+}
+
+// tomlEscapes maps the single-character escapes of basic strings.
+var tomlEscapes = map[byte]byte{
+	'b': '\b', 't': '\t', 'n': '\n', 'f': '\f', 'r': '\r', '"': '"', '\\': '\\',
+}
+
+// parseEscape parses the escape sequence at the current position into b.
+func (p *tomlParser) parseEscape(b *strings.Builder) error {
+	start := p.pos
+	e := p.peekAt(p.pos + 1)
+	p.pos += 2
+	if c, ok := tomlEscapes[e]; ok {
+		b.WriteByte(c)
+		return nil
+	}
+	size := 0
+	switch e {
+	case 'u':
+		size = 4
+	case 'U':
+		size = 8
+	default:
+		return p.errorAt(start, "invalid escape sequence \\%c", e)
+	}
+	if p.pos+size > len(p.src) {
+		return p.errorAt(start, "invalid escape sequence \\%c", e)
+	}
+	n, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(n)) {
+		return p.errorAt(start, "invalid escape sequence \\%c%s", e, p.src[p.pos:p.pos+size])
+	}
+	b.WriteRune(rune(n))
+	p.pos += size
+	return nil
+}
+
+// checkStringByte rejects control characters other than tab inside strings.
+func (p *tomlParser) checkStringByte(c byte) error {
+	if (c < 0x20 && c != '\t') || c == 0x7f {
+		return p.errorf("control character %q must be escaped", c)
+	}
+	return nil
+}
+
+// parseBasicString parses a "basic string" with escape sequences.
+func (p *tomlParser) parseBasicString() (string, error) {
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+		case '\n', '\r':
+			return "", p.errorAt(start, "unterminated string")
+		default:
+			if err := p.checkStringByte(c); err != nil {
+				return "", err
+			}
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorAt(start, "unterminated string")
+}
+
+// parseLiteralString parses a 'literal string', which has no escape sequences.
+func (p *tomlParser) parseLiteralString() (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; c {
+		case '\'':
+			p.pos++
+			return p.src[start+1 : p.pos-1], nil
+		case '\n', '\r':
+			return "", p.errorAt(start, "unterminated string")
+		default:
+			if err := p.checkStringByte(c); err != nil {
+				return "", err
+			}
+			p.pos++
+		}
+	}
+	return "", p.errorAt(start, "unterminated string")
+}
+
+// parseMultiLineString parses a multi-line basic string, delimited by three double
+// quotes, or a multi-line literal string, delimited by three single quotes, as given
+// by quote. A line break right after the opening delimiter is trimmed, and in basic
+// strings a backslash at the end of a line trims the line break and the whitespace
+// that follows.
+func (p *tomlParser) parseMultiLineString(quote byte) (string, error) {
+	start := p.pos
+	delimiter := strings.Repeat(string(quote), 3)
+	p.pos += 3
+	p.skipNewline()
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case strings.HasPrefix(p.src[p.pos:], delimiter):
+			// Up to two quotes may directly precede the closing delimiter.
+			n := 3
+			for n < 5 && p.peekAt(p.pos+n) == quote {
+				n++
+			}
+			b.WriteString(strings.Repeat(string(quote), n-3))
+			p.pos += n
+			return b.String(), nil
+		case c == '\\' && quote == '"':
+			next := p.pos + 1
+			for p.peekAt(next) == ' ' || p.peekAt(next) == '\t' {
+				next++
+			}
+			if n := p.peekAt(next); n == '\n' || (n == '\r' && p.peekAt(next+1) == '\n') {
+				for p.pos = next; p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0; p.pos++ {
+				}
+				continue
+			}
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+		case c == '\n' || c == '\r':
+			if !p.skipNewline() {
+				return "", p.errorf("control character %q must be escaped", c)
+			}
+			b.WriteByte('\n')
+		default:
+			if err := p.checkStringByte(c); err != nil {
+				return "", err
+			}
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorAt(start, "unterminated string")
+}
+
+// parseArray parses [1, 2, 3], which may span lines and contain comments.
+func (p *tomlParser) parseArray() ([]interface{}, error) {
+	start := p.pos
+	p.pos++
+	result := make([]interface{}, 0)
+	for {
+		p.skipBlankLines()
+		switch p.peekAt(p.pos) {
+		case 0:
+			return nil, p.errorAt(start, "unterminated array")
+		case ']':
+			p.pos++
+			return result, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+		p.skipBlankLines()
+		switch p.peekAt(p.pos) {
+		case ',':
+			p.pos++
+		case ']':
+		case 0:
+			return nil, p.errorAt(start, "unterminated array")
+		default:
+			return nil, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+// parseInlineTable parses {a = 1, b.c = 2}, which must fit on one line.
+func (p *tomlParser) parseInlineTable() (*tomlTable, error) {
+	p.pos++
+	table := newTOMLTable()
+	p.skipWhitespace()
+	if p.peekAt(p.pos) == '}' {
+		p.pos++
+		table.inline = true
+		return table, nil
+	}
+	for {
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		switch p.peekAt(p.pos) {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			table.inline = true
+			return table, nil
+		default:
+			return nil, p.errorf("expected ',' or '}' in inline table")
+		}
+	}
+}
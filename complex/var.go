@@ -0,0 +1,307 @@
+package complex
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graingo/mconv/basic"
+)
+
+// Var wraps a value of any type and converts it on demand with the basic and complex
+// converters. Conversion results are memoized, so repeated calls such as v.Int() only
+// convert once; slices and maps returned by Slice and Map are shared between calls.
+// A Var is safe for concurrent use, and a nil *Var behaves like a Var wrapping nil.
+// Copies of a Var share its value and memoized results. The typed converters such as
+// ToIntE accept a *Var and convert the wrapped value.
+type Var struct {
+	state *varState
+}
+
+// varState holds the wrapped value and its memoized conversions.
+type varState struct {
+	mu    sync.Mutex
+	value interface{}
+	cache map[string]varResult
+}
+
+// varResult is a memoized conversion result.
+type varResult struct {
+	value interface{}
+	err   error
+}
+
+// NewVar returns a Var wrapping value.
+func NewVar(value interface{}) *Var {
+	return &Var{state: &varState{value: value}}
+}
+
+// memoize returns the cached result of key, or converts the wrapped value and caches it.
+func memoize[T any](v *Var, key string, convert func(interface{}) (T, error)) (T, error) {
+	if v == nil || v.state == nil {
+		return convert(nil)
+	}
+	st := v.state
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if r, ok := st.cache[key]; ok {
+		result, _ := r.value.(T)
+		return result, r.err
+	}
+	result, err := convert(st.value)
+	if st.cache == nil {
+		st.cache = make(map[string]varResult)
+	}
+	st.cache[key] = varResult{value: result, err: err}
+	return result, err
+}
+
+// Interface returns the wrapped value.
+func (v *Var) Interface() interface{} {
+	if v == nil || v.state == nil {
+		return nil
+	}
+	v.state.mu.Lock()
+	defer v.state.mu.Unlock()
+	return v.state.value
+}
+
+// IsNil reports whether the wrapped value is nil, including nil pointers, maps and slices.
+func (v *Var) IsNil() bool {
+	value := v.Interface()
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// IsEmpty reports whether the wrapped value is nil, the zero value of its type,
+// or an empty string, slice, array or map.
+func (v *Var) IsEmpty() bool {
+	if v.IsNil() {
+		return true
+	}
+	rv := reflect.ValueOf(v.Interface())
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+// String converts the wrapped value to string.
+func (v *Var) String() string {
+	result, _ := v.StringE()
+	return result
+}
+
+// StringE converts the wrapped value to string with error.
+func (v *Var) StringE() (string, error) {
+	return memoize(v, "string", basic.ToStringE)
+}
+
+// Int converts the wrapped value to int.
+func (v *Var) Int() int {
+	result, _ := v.IntE()
+	return result
+}
+
+// IntE converts the wrapped value to int with error.
+func (v *Var) IntE() (int, error) {
+	return memoize(v, "int", basic.ToIntE)
+}
+
+// Int32 converts the wrapped value to int32.
+func (v *Var) Int32() int32 {
+	result, _ := v.Int32E()
+	return result
+}
+
+// Int32E converts the wrapped value to int32 with error.
+func (v *Var) Int32E() (int32, error) {
+	return memoize(v, "int32", basic.ToInt32E)
+}
+
+// Int64 converts the wrapped value to int64.
+func (v *Var) Int64() int64 {
+	result, _ := v.Int64E()
+	return result
+}
+
+// Int64E converts the wrapped value to int64 with error.
+func (v *Var) Int64E() (int64, error) {
+	return memoize(v, "int64", basic.ToInt64E)
+}
+
+// Uint converts the wrapped value to uint.
+func (v *Var) Uint() uint {
+	result, _ := v.UintE()
+	return result
+}
+
+// UintE converts the wrapped value to uint with error.
+func (v *Var) UintE() (uint, error) {
+	return memoize(v, "uint", basic.ToUintE)
+}
+
+// Uint64 converts the wrapped value to uint64.
+func (v *Var) Uint64() uint64 {
+	result, _ := v.Uint64E()
+	return result
+}
+
+// Uint64E converts the wrapped value to uint64 with error.
+func (v *Var) Uint64E() (uint64, error) {
+	return memoize(v, "uint64", basic.ToUint64E)
+}
+
+// Float32 converts the wrapped value to float32.
+func (v *Var) Float32() float32 {
+	result, _ := v.Float32E()
+	return result
+}
+
+// Float32E converts the wrapped value to float32 with error.
+func (v *Var) Float32E() (float32, error) {
+	return memoize(v, "float32", basic.ToFloat32E)
+}
+
+// Float64 converts the wrapped value to float64.
+func (v *Var) Float64() float64 {
+	result, _ := v.Float64E()
+	return result
+}
+
+// Float64E converts the wrapped value to float64 with error.
+func (v *Var) Float64E() (float64, error) {
+	return memoize(v, "float64", basic.ToFloat64E)
+}
+
+// Bool converts the wrapped value to bool.
+func (v *Var) Bool() bool {
+	result, _ := v.BoolE()
+	return result
+}
+
+// BoolE converts the wrapped value to bool with error.
+func (v *Var) BoolE() (bool, error) {
+	return memoize(v, "bool", basic.ToBoolE)
+}
+
+// Time converts the wrapped value to time.Time, trying the optional layouts first.
+func (v *Var) Time(layouts ...string) time.Time {
+	result, _ := v.TimeE(layouts...)
+	return result
+}
+
+// TimeE converts the wrapped value to time.Time with error.
+func (v *Var) TimeE(layouts ...string) (time.Time, error) {
+	// Results are memoized per set of layouts.
+	key := "time\x00" + strings.Join(layouts, "\x00")
+	return memoize(v, key, func(value interface{}) (time.Time, error) {
+		return basic.ToTimeE(value, layouts...)
+	})
+}
+
+// Duration converts the wrapped value to time.Duration.
+func (v *Var) Duration() time.Duration {
+	result, _ := v.DurationE()
+	return result
+}
+
+// DurationE converts the wrapped value to time.Duration with error.
+func (v *Var) DurationE() (time.Duration, error) {
+	return memoize(v, "duration", basic.ToDurationE)
+}
+
+// Slice converts the wrapped value to []interface{}.
+func (v *Var) Slice() []interface{} {
+	result, _ := v.SliceE()
+	return result
+}
+
+// SliceE converts the wrapped value to []interface{} with error.
+func (v *Var) SliceE() ([]interface{}, error) {
+	return memoize(v, "slice", ToSliceE)
+}
+
+// Strings converts the wrapped value to []string.
+func (v *Var) Strings() []string {
+	result, _ := v.StringsE()
+	return result
+}
+
+// StringsE converts the wrapped value to []string with error.
+func (v *Var) StringsE() ([]string, error) {
+	return memoize(v, "strings", ToStringSliceE)
+}
+
+// Map converts the wrapped value to map[string]interface{}.
+func (v *Var) Map() map[string]interface{} {
+	result, _ := v.MapE()
+	return result
+}
+
+// MapE converts the wrapped value to map[string]interface{} with error.
+func (v *Var) MapE() (map[string]interface{}, error) {
+	return memoize(v, "map", ToMapE)
+}
+
+// Struct decodes the wrapped value into the struct that pointer points to, see ToStructE.
+func (v *Var) Struct(pointer interface{}, hooks ...HookFunc) error {
+	return ToStructE(v.Interface(), pointer, hooks...)
+}
+
+// MarshalJSON implements json.Marshaler by encoding the wrapped value.
+func (v *Var) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Interface())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Numbers are kept as json.Number
+// so that no precision is lost before they are converted.
+func (v *Var) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	v.reset(value)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler using the string form of the wrapped value.
+func (v *Var) MarshalText() ([]byte, error) {
+	s, err := v.StringE()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by wrapping the text as a string.
+func (v *Var) UnmarshalText(text []byte) error {
+	v.reset(string(text))
+	return nil
+}
+
+// reset replaces the wrapped value and drops memoized conversions.
+// A zero Var, such as a struct field being unmarshaled, is given its state here.
+func (v *Var) reset(value interface{}) {
+	if v.state == nil {
+		v.state = &varState{value: value}
+		return
+	}
+	v.state.mu.Lock()
+	defer v.state.mu.Unlock()
+	v.state.value = value
+	v.state.cache = nil
+}
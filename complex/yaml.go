@@ -0,0 +1,558 @@
+package complex
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/graingo/mconv/internal"
+)
+
+// YAMLError describes invalid YAML input. It matches internal.ErrInvalidYAMLFormat
+// with errors.Is and unwraps to the underlying error.
+type YAMLError struct {
+	// Err describes the problem.
+	Err error
+	// Line and Column are the 1-based position of the problem in the input.
+	Line   int
+	Column int
+}
+
+// Error implements the error interface.
+func (e *YAMLError) Error() string {
+	return fmt.Sprintf("%v at line %d, column %d: %v", internal.ErrInvalidYAMLFormat, e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *YAMLError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is internal.ErrInvalidYAMLFormat.
+func (e *YAMLError) Is(target error) bool {
+	return target == internal.ErrInvalidYAMLFormat
+}
+
+// FromYAMLE decodes a single YAML document into target with error.
+// Structs are filled like ToStructE, so yaml tags apply along with the others;
+// other targets are converted with the same rules as struct fields.
+// See ToDocumentsFromYAMLE for the supported YAML subset.
+func FromYAMLE(yamlStr string, target interface{}, hooks ...HookFunc) error {
+	if yamlStr == "" {
+		return nil
+	}
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, but got %T", target)
+	}
+
+	value, err := decodeYAMLDocument(yamlStr)
+	if err != nil {
+		return internal.NewConversionError(yamlStr, "object", err)
+	}
+	if rv.Elem().Kind() == reflect.Struct {
+		return ToStructE(value, target, hooks...)
+	}
+	return setFieldValue(rv.Elem(), value, append(defaultHooks(), hooks...)...)
+}
+
+// FromYAML decodes a single YAML document into target.
+func FromYAML(yamlStr string, target interface{}, hooks ...HookFunc) {
+	_ = FromYAMLE(yamlStr, target, hooks...)
+}
+
+// ToMapFromYAMLE decodes a single YAML document into map[string]interface{} with error.
+// An empty document yields a nil map.
+func ToMapFromYAMLE(yamlStr string) (map[string]interface{}, error) {
+	if yamlStr == "" {
+		return nil, nil
+	}
+	value, err := decodeYAMLDocument(yamlStr)
+	if err != nil {
+		return nil, internal.NewConversionError(yamlStr, "map", err)
+	}
+	if value == nil {
+		return nil, nil
+	}
+	result, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, internal.NewConversionError(yamlStr, "map", fmt.Errorf("document is a %T, not a mapping", value))
+	}
+	return result, nil
+}
+
+// ToMapFromYAML decodes a single YAML document into map[string]interface{}.
+func ToMapFromYAML(yamlStr string) map[string]interface{} {
+	result, _ := ToMapFromYAMLE(yamlStr)
+	return result
+}
+
+// ToDocumentsFromYAMLE decodes every document of a YAML stream with error.
+//
+// The supported subset of YAML 1.2 covers block and flow mappings and sequences,
+// plain, quoted and multi-line scalars, literal (|) and folded (>) block scalars with
+// chomping indicators, comments, anchors and aliases, merge keys (<<), the !!str,
+// !!int, !!float, !!bool and !!null tags, and streams of documents separated by ---.
+// Plain scalars are resolved with the core schema into nil, bool, int, uint64, float64
+// or string, and quoted and block scalars stay strings. Mappings become
+// map[string]interface{} keyed by the text of each key, and sequences become
+// []interface{}. Complex keys (?) are not supported, other tags are ignored and
+// directives such as %YAML are skipped.
+func ToDocumentsFromYAMLE(yamlStr string) ([]interface{}, error) {
+	docs, err := newYAMLParser(yamlStr).parseStream()
+	if err != nil {
+		return nil, internal.NewConversionError(yamlStr, "documents", err)
+	}
+	return docs, nil
+}
+
+// ToDocumentsFromYAML decodes every document of a YAML stream.
+func ToDocumentsFromYAML(yamlStr string) []interface{} {
+	result, _ := ToDocumentsFromYAMLE(yamlStr)
+	return result
+}
+
+// decodeYAMLDocument parses yamlStr, which must contain at most one document.
+func decodeYAMLDocument(yamlStr string) (interface{}, error) {
+	p := newYAMLParser(yamlStr)
+	docs, err := p.parseStream()
+	if err != nil {
+		return nil, err
+	}
+	switch len(docs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return docs[0], nil
+	}
+	return nil, errors.New("input contains multiple documents, use ToDocumentsFromYAMLE")
+}
+
+// yamlParser is a recursive-descent parser for the supported YAML subset.
+type yamlParser struct {
+	src     string
+	pos     int
+	anchors map[string]interface{}
+}
+
+func newYAMLParser(src string) *yamlParser {
+	return &yamlParser{src: strings.TrimPrefix(src, "\ufeff"), anchors: make(map[string]interface{})}
+}
+
+// errorf reports a syntax error at the current position.
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+// errorAt reports a syntax error at offset pos.
+func (p *yamlParser) errorAt(pos int, format string, args ...interface{}) error {
+	if pos > len(p.src) {
+		pos = len(p.src)
+	}
+	line := 1 + strings.Count(p.src[:pos], "\n")
+	column := pos - strings.LastIndexByte(p.src[:pos], '\n')
+	return &YAMLError{Err: fmt.Errorf(format, args...), Line: line, Column: column}
+}
+
+// parseStream parses every document of the input.
+func (p *yamlParser) parseStream() ([]interface{}, error) {
+	docs := make([]interface{}, 0, 1)
+	for {
+		p.skipToContent()
+		// Skip directives such as %YAML 1.2.
+		for p.pos < len(p.src) && p.column(p.pos) == 0 && p.src[p.pos] == '%' {
+			p.skipLine()
+			p.skipToContent()
+		}
+		if p.pos >= len(p.src) {
+			return docs, nil
+		}
+
+		explicit := false
+		if p.isMarker("---") {
+			explicit = true
+			p.pos += 3
+			p.skipSpaces()
+			if p.atLineEnd() {
+				p.skipToContent()
+			}
+		} else if p.isMarker("...") {
+			p.pos += 3
+			continue
+		}
+
+		var doc interface{}
+		if p.pos < len(p.src) && !p.isMarker("---") && !p.isMarker("...") {
+			var err error
+			if doc, err = p.parseBlockNode(-1, true); err != nil {
+				return nil, err
+			}
+		} else if !explicit {
+			continue
+		}
+		docs = append(docs, doc)
+
+		p.skipToContent()
+		switch {
+		case p.pos >= len(p.src):
+			return docs, nil
+		case p.isMarker("..."):
+			p.pos += 3
+		case p.isMarker("---"):
+		default:
+			return nil, p.errorf("unexpected content after document")
+		}
+	}
+}
+
+// column returns the 0-based column of offset pos.
+func (p *yamlParser) column(pos int) int {
+	return pos - strings.LastIndexByte(p.src[:pos], '\n') - 1
+}
+
+// peekAt returns the byte at offset pos, or 0 at the end of the input.
+func (p *yamlParser) peekAt(pos int) byte {
+	if pos < len(p.src) {
+		return p.src[pos]
+	}
+	return 0
+}
+
+// isBlank reports whether c ends a token: a space, tab, line break or the end of input.
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == 0
+}
+
+// isMarker reports whether a document marker such as --- starts at the current position.
+func (p *yamlParser) isMarker(marker string) bool {
+	return p.markerAt(p.pos, marker)
+}
+
+// markerAt reports whether a document marker starts at offset pos.
+func (p *yamlParser) markerAt(pos int, marker string) bool {
+	return p.column(pos) == 0 && strings.HasPrefix(p.src[pos:], marker) && isBlank(p.peekAt(pos+3))
+}
+
+// isSequenceEntry reports whether a block sequence entry starts at pos.
+func (p *yamlParser) isSequenceEntry(pos int) bool {
+	return p.peekAt(pos) == '-' && isBlank(p.peekAt(pos+1))
+}
+
+// skipSpaces skips spaces and tabs.
+func (p *yamlParser) skipSpaces() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// atLineEnd reports whether only a comment or a line break follows on the current line.
+func (p *yamlParser) atLineEnd() bool {
+	c := p.peekAt(p.pos)
+	return c == 0 || c == '\n' || c == '\r' || c == '#'
+}
+
+// skipLine moves to the start of the next line.
+func (p *yamlParser) skipLine() {
+	if i := strings.IndexByte(p.src[p.pos:], '\n'); i >= 0 {
+		p.pos += i + 1
+	} else {
+		p.pos = len(p.src)
+	}
+}
+
+// skipToContent skips whitespace, line breaks and comments.
+func (p *yamlParser) skipToContent() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '#':
+			p.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+// parseBlockNode parses the node at the current position, which belongs to a parent
+// indented by parentIndent. Block collections are only allowed when collections is
+// true, that is when the node does not share its line with a mapping key.
+func (p *yamlParser) parseBlockNode(parentIndent int, collections bool) (interface{}, error) {
+	anchor, tag, err := p.parseProperties()
+	if err != nil {
+		return nil, err
+	}
+	if (anchor != "" || tag != "") && p.atLineEnd() {
+		// The node starts on the next line, or is empty.
+		p.skipToContent()
+		collections = true
+		if p.pos >= len(p.src) || p.isMarker("---") || p.isMarker("...") || p.column(p.pos) <= parentIndent {
+			return p.finishNode(anchor, tag, nil, "", false)
+		}
+	}
+
+	start := p.pos
+	switch c := p.peekAt(p.pos); {
+	case c == '*':
+		value, err := p.parseAlias()
+		if err != nil {
+			return nil, err
+		}
+		return p.finishNode(anchor, "", value, "", false)
+	case p.isSequenceEntry(p.pos):
+		if !collections {
+			return nil, p.errorf("block sequence entries are not allowed here")
+		}
+		value, err := p.parseBlockSequence(p.column(p.pos))
+		if err != nil {
+			return nil, err
+		}
+		return p.finishNode(anchor, "", value, "", false)
+	case c == '|' || c == '>':
+		raw, err := p.parseBlockScalar(parentIndent)
+		if err != nil {
+			return nil, err
+		}
+		return p.finishNode(anchor, tag, raw, raw, false)
+	case c == '[' || c == '{':
+		value, err := p.parseFlowNode()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.peekAt(p.pos) == ':' {
+			return nil, p.errorf("flow collections cannot be mapping keys")
+		}
+		return p.finishNode(anchor, tag, value, "", false)
+	case c == '?' && isBlank(p.peekAt(p.pos+1)):
+		return nil, p.errorf("complex mapping keys are not supported")
+	}
+
+	// A scalar, or the first key of a block mapping.
+	raw, plain, err := p.parseBlockScalarLine()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.peekAt(p.pos) == ':' && isBlank(p.peekAt(p.pos+1)) {
+		if !collections {
+			return nil, p.errorf("mapping values are not allowed here")
+		}
+		value, err := p.parseBlockMapping(p.column(start), raw, plain && raw == "<<")
+		if err != nil {
+			return nil, err
+		}
+		return p.finishNode(anchor, "", value, "", false)
+	}
+	if plain {
+		if raw, err = p.continuePlainScalar(raw, parentIndent); err != nil {
+			return nil, err
+		}
+	}
+	return p.finishNode(anchor, tag, raw, raw, plain)
+}
+
+// finishNode resolves a scalar with its tag and registers the node's anchor.
+// raw and plain describe the scalar, and value is used for other nodes.
+func (p *yamlParser) finishNode(anchor, tag string, value interface{}, raw string, plain bool) (interface{}, error) {
+	if s, ok := value.(string); ok && s == raw {
+		resolved, err := p.finishScalar(tag, raw, plain)
+		if err != nil {
+			return nil, err
+		}
+		value = resolved
+	}
+	if anchor != "" {
+		p.anchors[anchor] = value
+	}
+	return value, nil
+}
+
+// parseProperties parses an optional anchor (&name) and tag (!tag) in any order.
+func (p *yamlParser) parseProperties() (anchor, tag string, err error) {
+	for {
+		switch p.peekAt(p.pos) {
+		case '&':
+			if anchor != "" {
+				return "", "", p.errorf("a node can only have one anchor")
+			}
+			p.pos++
+			anchor = p.readName()
+			if anchor == "" {
+				return "", "", p.errorf("expected an anchor name")
+			}
+		case '!':
+			if tag != "" {
+				return "", "", p.errorf("a node can only have one tag")
+			}
+			start := p.pos
+			for p.pos < len(p.src) && !isBlank(p.src[p.pos]) && strings.IndexByte(",[]{}", p.src[p.pos]) < 0 {
+				p.pos++
+			}
+			tag = p.src[start:p.pos]
+		default:
+			return anchor, tag, nil
+		}
+		p.skipSpaces()
+	}
+}
+
+// readName reads an anchor or alias name.
+func (p *yamlParser) readName() string {
+	start := p.pos
+	for p.pos < len(p.src) && !isBlank(p.src[p.pos]) && strings.IndexByte(",[]{}", p.src[p.pos]) < 0 {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// parseAlias parses *name and returns the anchored value.
+func (p *yamlParser) parseAlias() (interface{}, error) {
+	start := p.pos
+	p.pos++
+	name := p.readName()
+	value, ok := p.anchors[name]
+	if !ok {
+		return nil, p.errorAt(start, "unknown anchor %q", name)
+	}
+	return value, nil
+}
+
+// parseBlockSequence parses a block sequence whose entries start at column indent.
+func (p *yamlParser) parseBlockSequence(indent int) ([]interface{}, error) {
+	result := make([]interface{}, 0)
+	for {
+		p.pos++ // the entry indicator
+		p.skipSpaces()
+		var item interface{}
+		if p.atLineEnd() {
+			p.skipToContent()
+			if p.pos < len(p.src) && !p.isMarker("---") && !p.isMarker("...") && p.column(p.pos) > indent {
+				var err error
+				if item, err = p.parseBlockNode(indent, true); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			var err error
+			if item, err = p.parseBlockNode(indent, true); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, item)
+
+		p.skipToContent()
+		if p.pos >= len(p.src) || p.isMarker("---") || p.isMarker("...") {
+			return result, nil
+		}
+		switch column := p.column(p.pos); {
+		case column < indent:
+			return result, nil
+		case column > indent:
+			return nil, p.errorf("bad indentation of a sequence entry")
+		}
+		if !p.isSequenceEntry(p.pos) {
+			return result, nil
+		}
+	}
+}
+
+// parseBlockMapping parses a block mapping at column indent whose first key has been
+// read; the current position is at the ':' after it. merge reports whether the key
+// is the merge key <<.
+func (p *yamlParser) parseBlockMapping(indent int, firstKey string, merge bool) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	var merges []interface{}
+	key := firstKey
+	for {
+		keyPos := p.pos
+		p.pos++ // the ':'
+		p.skipSpaces()
+
+		var (
+			value interface{}
+			err   error
+		)
+		if p.atLineEnd() {
+			p.skipToContent()
+			if p.pos < len(p.src) && !p.isMarker("---") && !p.isMarker("...") {
+				// Sequences may be indented at the same level as their key.
+				column := p.column(p.pos)
+				if column > indent || (column == indent && p.isSequenceEntry(p.pos)) {
+					if value, err = p.parseBlockNode(indent, true); err != nil {
+						return nil, err
+					}
+				}
+			}
+		} else if value, err = p.parseBlockNode(indent, false); err != nil {
+			return nil, err
+		}
+
+		if merge {
+			merges = append(merges, value)
+		} else {
+			if _, exists := result[key]; exists {
+				return nil, p.errorAt(keyPos, "duplicate key %q", key)
+			}
+			result[key] = value
+		}
+
+		p.skipToContent()
+		if p.pos >= len(p.src) || p.isMarker("---") || p.isMarker("...") {
+			break
+		}
+		column := p.column(p.pos)
+		if column < indent {
+			break
+		}
+		if column > indent {
+			return nil, p.errorf("bad indentation of a mapping entry")
+		}
+		keyStart := p.pos
+		switch c := p.peekAt(p.pos); {
+		case c == '?' && isBlank(p.peekAt(p.pos+1)):
+			return nil, p.errorf("complex mapping keys are not supported")
+		case p.isSequenceEntry(p.pos), c == '[', c == '{', c == '*', c == '&', c == '!', c == '|', c == '>':
+			return nil, p.errorf("expected a mapping key")
+		}
+		raw, plain, err := p.parseBlockScalarLine()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.peekAt(p.pos) != ':' || !isBlank(p.peekAt(p.pos+1)) {
+			return nil, p.errorAt(keyStart, "expected a mapping key")
+		}
+		key = raw
+		merge = plain && raw == "<<"
+	}
+
+	if err := mergeYAMLKeys(result, merges); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeYAMLKeys applies merge keys: the keys of each merged mapping are added unless
+// the mapping or an earlier merged mapping already has them.
+func mergeYAMLKeys(result map[string]interface{}, merges []interface{}) error {
+	for _, merge := range merges {
+		sources := []interface{}{merge}
+		if list, ok := merge.([]interface{}); ok {
+			sources = list
+		}
+		for _, source := range sources {
+			m, ok := source.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("merge key values must be mappings, but got %T", source)
+			}
+			for k, v := range m {
+				if _, exists := result[k]; !exists {
+					result[k] = v
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,710 @@
+package complex
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// yamlEscapes maps the single-character escapes of double-quoted scalars.
+var yamlEscapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v",
+	'f': "\f", 'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\",
+	'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+}
+
+// finishScalar applies tag to a scalar. Untagged plain scalars are resolved with the
+// core schema and other untagged scalars stay strings.
+func (p *yamlParser) finishScalar(tag, raw string, plain bool) (interface{}, error) {
+	switch tag {
+	case "":
+		if !plain {
+			return raw, nil
+		}
+		return resolveYAMLScalar(raw), nil
+	case "!", "!!str":
+		return raw, nil
+	case "!!int", "!!float", "!!bool", "!!null":
+	default:
+		// Unknown tags are ignored.
+		return p.finishScalar("", raw, plain)
+	}
+
+	switch v := resolveYAMLScalar(raw).(type) {
+	case nil:
+		if tag == "!!null" {
+			return nil, nil
+		}
+	case bool:
+		if tag == "!!bool" {
+			return v, nil
+		}
+	case int:
+		if tag == "!!int" {
+			return v, nil
+		}
+		if tag == "!!float" {
+			return float64(v), nil
+		}
+	case uint64:
+		if tag == "!!int" {
+			return v, nil
+		}
+		if tag == "!!float" {
+			return float64(v), nil
+		}
+	case float64:
+		if tag == "!!float" {
+			return v, nil
+		}
+	}
+	return nil, p.errorf("cannot resolve %q as %s", raw, tag)
+}
+
+// resolveYAMLScalar resolves a plain scalar with the YAML 1.2 core schema.
+func resolveYAMLScalar(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+	if n, ok := resolveYAMLInt(s); ok {
+		return n
+	}
+	if isYAMLFloat(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// resolveYAMLInt resolves decimal, 0o octal and 0x hexadecimal integers into int,
+// or uint64 when they are too large for int. Decimal integers that are too large
+// for both become float64.
+func resolveYAMLInt(s string) (interface{}, bool) {
+	sign, digits, base := "", s, 10
+	switch {
+	case strings.HasPrefix(s, "0o"):
+		digits, base = s[2:], 8
+	case strings.HasPrefix(s, "0x"):
+		digits, base = s[2:], 16
+	case s != "" && (s[0] == '+' || s[0] == '-'):
+		sign, digits = s[:1], s[1:]
+	}
+	if digits == "" {
+		return nil, false
+	}
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		if !(c >= '0' && c <= '7') && !(base >= 10 && (c == '8' || c == '9')) && !(base == 16 && isHexDigit(c)) {
+			return nil, false
+		}
+	}
+	if n, err := strconv.ParseInt(sign+digits, base, strconv.IntSize); err == nil {
+		return int(n), true
+	}
+	if sign != "-" {
+		if u, err := strconv.ParseUint(digits, base, 64); err == nil {
+			return u, true
+		}
+	}
+	if base == 10 {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// isYAMLFloat reports whether s matches the core schema float syntax
+// [-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?.
+func isYAMLFloat(s string) bool {
+	digits := func(i int) int {
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		return i
+	}
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	mantissa := i
+	i = digits(i)
+	integer := i > mantissa
+	if i < len(s) && s[i] == '.' {
+		fraction := i + 1
+		i = digits(fraction)
+		if !integer && i == fraction {
+			return false
+		}
+	} else if !integer {
+		return false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		exponent := i
+		if i = digits(i); i == exponent {
+			return false
+		}
+	}
+	return i == len(s)
+}
+
+// parseBlockScalarLine reads a quoted scalar, or a plain scalar that ends at the end
+// of the line, a comment or a mapping value indicator. plain reports whether the
+// scalar is unquoted.
+func (p *yamlParser) parseBlockScalarLine() (raw string, plain bool, err error) {
+	switch c := p.peekAt(p.pos); c {
+	case '"':
+		raw, err = p.parseDoubleQuoted()
+		return raw, false, err
+	case '\'':
+		raw, err = p.parseSingleQuoted()
+		return raw, false, err
+	case '@', '`':
+		return "", false, p.errorf("character %q is reserved", c)
+	}
+	start := p.pos
+	p.pos = p.plainLineEnd(p.pos, false)
+	return strings.TrimRight(p.src[start:p.pos], " \t"), true, nil
+}
+
+// plainLineEnd returns the offset at which a plain scalar starting at pos ends on its
+// line. Inside flow collections the scalar also ends at flow indicators.
+func (p *yamlParser) plainLineEnd(pos int, flow bool) int {
+	for ; pos < len(p.src); pos++ {
+		switch p.src[pos] {
+		case '\n', '\r':
+			return pos
+		case ':':
+			if next := p.peekAt(pos + 1); isBlank(next) || (flow && strings.IndexByte(",[]{}", next) >= 0) {
+				return pos
+			}
+		case '#':
+			if pos > 0 && (p.src[pos-1] == ' ' || p.src[pos-1] == '\t') {
+				return pos
+			}
+		case ',', '[', ']', '{', '}':
+			if flow {
+				return pos
+			}
+		}
+	}
+	return pos
+}
+
+// continuePlainScalar appends the continuation lines of a multi-line plain scalar,
+// which must be indented more than parentIndent. A single line break folds into a
+// space, and each empty line in between becomes a line feed.
+func (p *yamlParser) continuePlainScalar(raw string, parentIndent int) (string, error) {
+	b := []byte(raw)
+	for {
+		p.skipSpaces()
+		if c := p.peekAt(p.pos); c != '\n' && c != '\r' {
+			// The end of the input or a comment, which ends the scalar.
+			return string(b), nil
+		}
+
+		next, breaks := p.pos, 0
+		for {
+			if p.src[next] == '\r' {
+				next++
+			}
+			if next < len(p.src) && p.src[next] == '\n' {
+				next++
+			}
+			breaks++
+			for next < len(p.src) && (p.src[next] == ' ' || p.src[next] == '\t') {
+				next++
+			}
+			if c := p.peekAt(next); c != '\n' && c != '\r' {
+				break
+			}
+		}
+		if next >= len(p.src) || p.column(next) <= parentIndent || p.src[next] == '#' ||
+			p.markerAt(next, "---") || p.markerAt(next, "...") {
+			return string(b), nil
+		}
+
+		if breaks == 1 {
+			b = append(b, ' ')
+		}
+		for i := 1; i < breaks; i++ {
+			b = append(b, '\n')
+		}
+		p.pos = p.plainLineEnd(next, false)
+		if p.peekAt(p.pos) == ':' {
+			return "", p.errorf("mapping values are not allowed here")
+		}
+		b = append(b, strings.TrimRight(p.src[next:p.pos], " \t")...)
+	}
+}
+
+// parseBlockScalar parses a literal (|) or folded (>) block scalar, including its
+// chomping (- or +) and indentation indicators.
+func (p *yamlParser) parseBlockScalar(parentIndent int) (string, error) {
+	literal := p.src[p.pos] == '|'
+	p.pos++
+	var chomp byte
+	explicit := 0
+	for {
+		c := p.peekAt(p.pos)
+		if (c == '-' || c == '+') && chomp == 0 {
+			chomp = c
+		} else if c >= '1' && c <= '9' && explicit == 0 {
+			explicit = int(c - '0')
+		} else {
+			break
+		}
+		p.pos++
+	}
+	p.skipSpaces()
+	if !p.atLineEnd() {
+		return "", p.errorf("invalid block scalar header")
+	}
+	p.skipLine()
+
+	indent := 0
+	if explicit > 0 {
+		if parentIndent > 0 {
+			indent = parentIndent
+		}
+		indent += explicit
+	} else {
+		// The first line with content sets the indentation.
+		indent = parentIndent + 1
+		for pos := p.pos; pos < len(p.src); {
+			spaces := 0
+			for pos+spaces < len(p.src) && p.src[pos+spaces] == ' ' {
+				spaces++
+			}
+			if c := p.peekAt(pos + spaces); c != '\n' && c != '\r' {
+				if c != 0 && spaces > parentIndent {
+					indent = spaces
+				}
+				break
+			}
+			pos += spaces
+			p.skipLineBreakAt(&pos)
+		}
+	}
+
+	var lines []string
+	finalBreak := false
+	for p.pos < len(p.src) && !p.markerAt(p.pos, "---") && !p.markerAt(p.pos, "...") {
+		end := strings.IndexByte(p.src[p.pos:], '\n')
+		if end < 0 {
+			end = len(p.src)
+		} else {
+			end += p.pos
+		}
+		line := strings.TrimSuffix(p.src[p.pos:end], "\r")
+		spaces := len(line) - len(strings.TrimLeft(line, " "))
+		if spaces == len(line) {
+			lines = append(lines, "")
+		} else if spaces < indent {
+			break
+		} else {
+			lines = append(lines, line[indent:])
+			finalBreak = end < len(p.src)
+		}
+		p.pos = end
+		if p.pos < len(p.src) {
+			p.pos++
+		}
+	}
+
+	content := len(lines)
+	for content > 0 && lines[content-1] == "" {
+		content--
+	}
+	var b strings.Builder
+	if literal {
+		b.WriteString(strings.Join(lines[:content], "\n"))
+	} else {
+		b.WriteString(foldYAMLLines(lines[:content]))
+	}
+	switch chomp {
+	case '-':
+	case '+':
+		if content > 0 && finalBreak {
+			b.WriteByte('\n')
+		}
+		b.WriteString(strings.Repeat("\n", len(lines)-content))
+	default:
+		if content > 0 && finalBreak {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String(), nil
+}
+
+// foldYAMLLines joins the lines of a folded block scalar. A line break between two
+// lines of text becomes a space, and is dropped before empty lines that are followed
+// by text, so that each empty line stands for one line feed. Line breaks around
+// more-indented lines are kept.
+func foldYAMLLines(lines []string) string {
+	text := func(line string) bool {
+		return line != "" && line[0] != ' ' && line[0] != '\t'
+	}
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			switch prev := lines[i-1]; {
+			case text(prev) && text(line):
+				b.WriteByte(' ')
+			case text(prev) && line == "":
+				next := i
+				for next < len(lines) && lines[next] == "" {
+					next++
+				}
+				if next == len(lines) || !text(lines[next]) {
+					b.WriteByte('\n')
+				}
+			default:
+				b.WriteByte('\n')
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// skipLineBreakAt moves *pos past a line break.
+func (p *yamlParser) skipLineBreakAt(pos *int) {
+	if p.peekAt(*pos) == '\r' {
+		*pos++
+	}
+	if p.peekAt(*pos) == '\n' {
+		*pos++
+	}
+}
+
+// parseDoubleQuoted parses a double-quoted scalar and its escape sequences.
+func (p *yamlParser) parseDoubleQuoted() (string, error) {
+	start := p.pos
+	p.pos++
+	var b []byte
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; c {
+		case '"':
+			p.pos++
+			return string(b), nil
+		case '\n', '\r':
+			b = p.foldQuotedBreak(b)
+		case '\\':
+			p.pos++
+			e := p.peekAt(p.pos)
+			if e == '\n' || e == '\r' {
+				// An escaped line break joins the lines without a space.
+				p.skipLineBreakAt(&p.pos)
+				p.skipSpaces()
+				continue
+			}
+			size := 0
+			switch e {
+			case 'x':
+				size = 2
+			case 'u':
+				size = 4
+			case 'U':
+				size = 8
+			default:
+				s, ok := yamlEscapes[e]
+				if !ok {
+					return "", p.errorAt(p.pos-1, "invalid escape sequence \\%c", e)
+				}
+				b = append(b, s...)
+				p.pos++
+				continue
+			}
+			p.pos++
+			if p.pos+size > len(p.src) {
+				return "", p.errorAt(p.pos-2, "invalid escape sequence \\%c", e)
+			}
+			n, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(n)) {
+				return "", p.errorAt(p.pos-2, "invalid escape sequence \\%c%s", e, p.src[p.pos:p.pos+size])
+			}
+			b = utf8.AppendRune(b, rune(n))
+			p.pos += size
+		default:
+			b = append(b, c)
+			p.pos++
+		}
+	}
+	return "", p.errorAt(start, "unterminated quoted scalar")
+}
+
+// parseSingleQuoted parses a single-quoted scalar, in which two quotes stand for one.
+func (p *yamlParser) parseSingleQuoted() (string, error) {
+	start := p.pos
+	p.pos++
+	var b []byte
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; c {
+		case '\'':
+			if p.peekAt(p.pos+1) == '\'' {
+				b = append(b, '\'')
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			return string(b), nil
+		case '\n', '\r':
+			b = p.foldQuotedBreak(b)
+		default:
+			b = append(b, c)
+			p.pos++
+		}
+	}
+	return "", p.errorAt(start, "unterminated quoted scalar")
+}
+
+// foldQuotedBreak folds the line breaks at the current position inside a quoted
+// scalar: a single break becomes a space and each following empty line a line feed.
+// Whitespace around the breaks is dropped.
+func (p *yamlParser) foldQuotedBreak(b []byte) []byte {
+	b = bytes.TrimRight(b, " \t")
+	breaks := 0
+	for c := p.peekAt(p.pos); c == '\n' || c == '\r'; c = p.peekAt(p.pos) {
+		p.skipLineBreakAt(&p.pos)
+		p.skipSpaces()
+		breaks++
+	}
+	if breaks == 1 {
+		return append(b, ' ')
+	}
+	for i := 1; i < breaks; i++ {
+		b = append(b, '\n')
+	}
+	return b
+}
+
+// parseFlowNode parses a flow sequence or flow mapping at the current position.
+func (p *yamlParser) parseFlowNode() (interface{}, error) {
+	if p.src[p.pos] == '[' {
+		return p.parseFlowSequence()
+	}
+	return p.parseFlowMapping()
+}
+
+// parseFlowSequence parses [a, b, c]. An entry written as key: value is a mapping
+// with a single pair.
+func (p *yamlParser) parseFlowSequence() ([]interface{}, error) {
+	start := p.pos
+	p.pos++
+	result := make([]interface{}, 0)
+	for {
+		p.skipToContent()
+		switch p.peekAt(p.pos) {
+		case 0:
+			return nil, p.errorAt(start, "unterminated flow collection")
+		case ']':
+			p.pos++
+			return result, nil
+		case ',':
+			return nil, p.errorf("unexpected ','")
+		}
+
+		var item interface{}
+		if c := p.peekAt(p.pos); strings.IndexByte("[{*&!", c) < 0 {
+			raw, plain, err := p.parseFlowScalar()
+			if err != nil {
+				return nil, err
+			}
+			p.skipToContent()
+			if p.isFlowColon(!plain) {
+				p.pos++
+				value, err := p.parseFlowPairValue(']')
+				if err != nil {
+					return nil, err
+				}
+				item = map[string]interface{}{raw: value}
+			} else if item, err = p.finishScalar("", raw, plain); err != nil {
+				return nil, err
+			}
+		} else {
+			var err error
+			if item, err = p.parseFlowValue(); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, item)
+		if err := p.flowSeparator(start, ']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseFlowMapping parses {a: 1, b: 2}. A key without a value maps to nil.
+func (p *yamlParser) parseFlowMapping() (map[string]interface{}, error) {
+	start := p.pos
+	p.pos++
+	result := make(map[string]interface{})
+	var merges []interface{}
+	for {
+		p.skipToContent()
+		keyStart := p.pos
+		switch c := p.peekAt(p.pos); c {
+		case 0:
+			return nil, p.errorAt(start, "unterminated flow collection")
+		case '}':
+			p.pos++
+			if err := mergeYAMLKeys(result, merges); err != nil {
+				return nil, err
+			}
+			return result, nil
+		case ',':
+			return nil, p.errorf("unexpected ','")
+		case '[', '{':
+			return nil, p.errorf("flow collections cannot be mapping keys")
+		case '*', '&', '!':
+			return nil, p.errorf("mapping keys cannot have anchors, aliases or tags")
+		case '?':
+			if isBlank(p.peekAt(p.pos + 1)) {
+				return nil, p.errorf("complex mapping keys are not supported")
+			}
+		}
+
+		key, plain, err := p.parseFlowScalar()
+		if err != nil {
+			return nil, err
+		}
+		p.skipToContent()
+		var value interface{}
+		if p.isFlowColon(!plain) {
+			p.pos++
+			if value, err = p.parseFlowPairValue('}'); err != nil {
+				return nil, err
+			}
+		}
+		if plain && key == "<<" {
+			merges = append(merges, value)
+		} else {
+			if _, exists := result[key]; exists {
+				return nil, p.errorAt(keyStart, "duplicate key %q", key)
+			}
+			result[key] = value
+		}
+		if err := p.flowSeparator(start, '}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isFlowColon reports whether a mapping value indicator is at the current position.
+// After a quoted key the indicator may be directly followed by the value.
+func (p *yamlParser) isFlowColon(adjacent bool) bool {
+	if p.peekAt(p.pos) != ':' {
+		return false
+	}
+	next := p.peekAt(p.pos + 1)
+	return adjacent || isBlank(next) || strings.IndexByte(",[]{}", next) >= 0
+}
+
+// parseFlowPairValue parses the value after the ':' of a flow mapping entry, which
+// may be empty.
+func (p *yamlParser) parseFlowPairValue(closer byte) (interface{}, error) {
+	p.skipToContent()
+	if c := p.peekAt(p.pos); c == ',' || c == closer {
+		return nil, nil
+	}
+	return p.parseFlowValue()
+}
+
+// flowSeparator consumes the ',' after an entry of the flow collection starting at
+// start, or checks that the collection ends with closer.
+func (p *yamlParser) flowSeparator(start int, closer byte) error {
+	p.skipToContent()
+	switch p.peekAt(p.pos) {
+	case ',':
+		p.pos++
+		return nil
+	case closer:
+		return nil
+	case 0:
+		return p.errorAt(start, "unterminated flow collection")
+	}
+	return p.errorf("expected ',' or '%c'", closer)
+}
+
+// parseFlowValue parses a node inside a flow collection.
+func (p *yamlParser) parseFlowValue() (interface{}, error) {
+	anchor, tag, err := p.parseProperties()
+	if err != nil {
+		return nil, err
+	}
+	p.skipToContent()
+	switch c := p.peekAt(p.pos); c {
+	case '*':
+		value, err := p.parseAlias()
+		if err != nil {
+			return nil, err
+		}
+		return p.finishNode(anchor, "", value, "", false)
+	case '[', '{':
+		value, err := p.parseFlowNode()
+		if err != nil {
+			return nil, err
+		}
+		return p.finishNode(anchor, tag, value, "", false)
+	case ',', ']', '}':
+		return p.finishNode(anchor, tag, nil, "", false)
+	}
+	raw, plain, err := p.parseFlowScalar()
+	if err != nil {
+		return nil, err
+	}
+	return p.finishNode(anchor, tag, raw, raw, plain)
+}
+
+// parseFlowScalar parses a quoted scalar, or a plain scalar that ends at a flow
+// indicator and may continue on following lines.
+func (p *yamlParser) parseFlowScalar() (raw string, plain bool, err error) {
+	switch c := p.peekAt(p.pos); c {
+	case '"':
+		raw, err = p.parseDoubleQuoted()
+		return raw, false, err
+	case '\'':
+		raw, err = p.parseSingleQuoted()
+		return raw, false, err
+	case ',', '[', ']', '{', '}', '@', '`':
+		return "", false, p.errorf("unexpected character %q", c)
+	}
+	var parts []string
+	for {
+		start := p.pos
+		p.pos = p.plainLineEnd(p.pos, true)
+		parts = append(parts, strings.TrimRight(p.src[start:p.pos], " \t"))
+		if c := p.peekAt(p.pos); c != '\n' && c != '\r' {
+			return strings.Join(parts, " "), true, nil
+		}
+		// Continue on the next line unless it starts with an indicator or a comment.
+		end := p.pos
+		for c := p.peekAt(p.pos); isBlank(c) && c != 0; c = p.peekAt(p.pos) {
+			p.pos++
+		}
+		c := p.peekAt(p.pos)
+		if c == 0 || strings.IndexByte(",[]{}#", c) >= 0 || p.isFlowColon(false) {
+			p.pos = end
+			return strings.Join(parts, " "), true, nil
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package complex_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graingo/mconv/complex"
+)
+
+func TestFlatten(t *testing.T) {
+	source := map[string]interface{}{
+		"db": map[string]interface{}{
+			"host":  "x",
+			"ports": []int{1, 2},
+			"opts":  map[string]string{"ssl": "on"},
+		},
+		"name":  "app",
+		"empty": map[string]interface{}{},
+		"none":  []string{},
+		"raw":   []byte("ab"),
+	}
+	expected := map[string]interface{}{
+		"db.host":     "x",
+		"db.ports.0":  1,
+		"db.ports.1":  2,
+		"db.opts.ssl": "on",
+		"name":        "app",
+		"empty":       map[string]interface{}{},
+		"none":        []string{},
+		"raw":         []byte("ab"),
+	}
+	if got := complex.Flatten(source, "."); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Flatten = %v; want %v", got, expected)
+	}
+
+	got := complex.Flatten(map[string]interface{}{"db": map[string]interface{}{"host": "x"}}, "__")
+	if !reflect.DeepEqual(got, map[string]interface{}{"db__host": "x"}) {
+		t.Errorf("Flatten with __ = %v", got)
+	}
+	if complex.Flatten(nil, ".") != nil {
+		t.Error("Flatten(nil) should be nil")
+	}
+}
+
+func TestUnflattenE(t *testing.T) {
+	source := map[string]interface{}{
+		"db.host":     "x",
+		"db.ports.0":  1,
+		"db.ports.1":  2,
+		"db.opts.ssl": "on",
+		"sparse.0":    "a",
+		"sparse.2":    "c",
+		"name":        "app",
+	}
+	expected := map[string]interface{}{
+		"db": map[string]interface{}{
+			"host":  "x",
+			"ports": []interface{}{1, 2},
+			"opts":  map[string]interface{}{"ssl": "on"},
+		},
+		"sparse": map[string]interface{}{"0": "a", "2": "c"},
+		"name":   "app",
+	}
+	got, err := complex.UnflattenE(source, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("UnflattenE = %v; want %v", got, expected)
+	}
+
+	// Top-level numeric keys stay a map.
+	got, err = complex.UnflattenE(map[string]interface{}{"0": "a"}, ".")
+	if err != nil || !reflect.DeepEqual(got, map[string]interface{}{"0": "a"}) {
+		t.Errorf("UnflattenE numeric root = %v, %v", got, err)
+	}
+
+	// Map leaves are merged with dotted keys.
+	got, err = complex.UnflattenE(map[string]interface{}{
+		"db":      map[string]interface{}{"host": "x"},
+		"db.port": 5432,
+	}, ".")
+	if err != nil || !reflect.DeepEqual(got, map[string]interface{}{"db": map[string]interface{}{"host": "x", "port": 5432}}) {
+		t.Errorf("UnflattenE merge = %v, %v", got, err)
+	}
+
+	if _, err := complex.UnflattenE(map[string]interface{}{"a": 1, "a.b": 2}, "."); err == nil {
+		t.Error("expected error for conflicting keys")
+	}
+	if _, err := complex.UnflattenE(map[string]interface{}{"a": 1}, ""); err == nil {
+		t.Error("expected error for empty separator")
+	}
+}
+
+func TestFlattenRoundTrip(t *testing.T) {
+	type Config struct {
+		DB struct {
+			Host  string `json:"host"`
+			Ports []int  `json:"ports"`
+		} `json:"db"`
+	}
+
+	env := map[string]interface{}{
+		"db__host":     "localhost",
+		"db__ports__0": "5432",
+		"db__ports__1": "5433",
+	}
+	nested, err := complex.UnflattenE(env, "__")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var cfg Config
+	if err := complex.ToStructE(nested, &cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DB.Host != "localhost" || !reflect.DeepEqual(cfg.DB.Ports, []int{5432, 5433}) {
+		t.Errorf("ToStructE(Unflatten) = %+v", cfg)
+	}
+
+	if back := complex.Flatten(nested, "__"); !reflect.DeepEqual(back, env) {
+		t.Errorf("Flatten(Unflatten) = %v; want %v", back, env)
+	}
+}
+
+func TestFlattenWithOptions(t *testing.T) {
+	source := map[string]interface{}{
+		"db": map[string]interface{}{
+			"ports": []int{1, 2},
+			"hosts": []interface{}{map[string]interface{}{"name": "a"}, []string{"x", "y"}},
+		},
+	}
+
+	brackets := complex.FlattenOptions{Sep: ".", IndexStyle: complex.IndexBrackets}
+	flat := complex.FlattenWithOptions(source, brackets)
+	expected := map[string]interface{}{
+		"db.ports[0]":      1,
+		"db.ports[1]":      2,
+		"db.hosts[0].name": "a",
+		"db.hosts[1][0]":   "x",
+		"db.hosts[1][1]":   "y",
+	}
+	if !reflect.DeepEqual(flat, expected) {
+		t.Errorf("FlattenWithOptions(IndexBrackets) = %v; want %v", flat, expected)
+	}
+	back, err := complex.UnflattenWithOptionsE(flat, brackets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]interface{}{
+		"db": map[string]interface{}{
+			"ports": []interface{}{1, 2},
+			"hosts": []interface{}{map[string]interface{}{"name": "a"}, []interface{}{"x", "y"}},
+		},
+	}
+	if !reflect.DeepEqual(back, want) {
+		t.Errorf("UnflattenWithOptionsE(IndexBrackets) = %v; want %v", back, want)
+	}
+
+	none := complex.FlattenOptions{Sep: "__", IndexStyle: complex.IndexNone}
+	flat = complex.FlattenWithOptions(source, none)
+	if !reflect.DeepEqual(flat["db__ports"], []int{1, 2}) || len(flat) != 2 {
+		t.Errorf("FlattenWithOptions(IndexNone) = %v", flat)
+	}
+}
+
+func TestUnflattenWithOptionsE(t *testing.T) {
+	// Numeric keys only become slices with IndexSep.
+	source := map[string]interface{}{"codes.0": "ok", "codes.1": "fail"}
+	for _, style := range []complex.IndexStyle{complex.IndexBrackets, complex.IndexNone} {
+		got, err := complex.UnflattenWithOptionsE(source, complex.FlattenOptions{Sep: ".", IndexStyle: style})
+		want := map[string]interface{}{"codes": map[string]interface{}{"0": "ok", "1": "fail"}}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("style %d: UnflattenWithOptionsE = %v, %v; want %v", style, got, err, want)
+		}
+	}
+
+	brackets := complex.FlattenOptions{Sep: ".", IndexStyle: complex.IndexBrackets}
+	tests := []struct {
+		name   string
+		source map[string]interface{}
+	}{
+		{"sparse indices", map[string]interface{}{"a[0]": 1, "a[2]": 3}},
+		{"huge index", map[string]interface{}{"a[999999999]": 1}},
+		{"indices mixed with keys", map[string]interface{}{"a[0]": 1, "a.b": 2}},
+	}
+	for _, tt := range tests {
+		if _, err := complex.UnflattenWithOptionsE(tt.source, brackets); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+
+	// Brackets that are not indices are part of the key.
+	got, err := complex.UnflattenWithOptionsE(map[string]interface{}{"a[x]": 1, "[0]": 2}, brackets)
+	if err != nil || !reflect.DeepEqual(got, map[string]interface{}{"a[x]": 1, "[0]": 2}) {
+		t.Errorf("UnflattenWithOptionsE literal brackets = %v, %v", got, err)
+	}
+	if _, err := complex.UnflattenWithOptionsE(map[string]interface{}{"a": 1}, complex.FlattenOptions{}); err == nil {
+		t.Error("expected error for empty separator")
+	}
+}
@@ -0,0 +1,114 @@
+package complex_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/graingo/mconv"
+	"github.com/graingo/mconv/internal"
+)
+
+func TestToMapFromINIE(t *testing.T) {
+	input := `; global settings
+app_mode = production
+
+[paths]
+data = /var/lib/app
+# an inline path
+logs: "/var/log/app"
+
+[server.http]
+port = 8080
+  timeout = 30s
+description = first line
+    second line
+	third line
+command = run --verbose \
+          --color
+empty =
+
+[paths]
+tmp = '/tmp'
+data = /srv/data
+`
+	result, err := mconv.ToMapFromINIE(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"app_mode": "production",
+		"paths": map[string]interface{}{
+			"data": "/srv/data",
+			"logs": "/var/log/app",
+			"tmp":  "/tmp",
+		},
+		"server": map[string]interface{}{
+			"http": map[string]interface{}{
+				"port":        "8080\ntimeout = 30s",
+				"description": "first line\nsecond line\nthird line",
+				"command":     "run --verbose --color",
+				"empty":       "",
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ToMapFromINIE() = %#v; want %#v", result, expected)
+	}
+
+	if result, err := mconv.ToMapFromINIE(""); result != nil || err != nil {
+		t.Errorf("ToMapFromINIE(\"\") = %v, %v; want nil, nil", result, err)
+	}
+}
+
+func TestINIToStruct(t *testing.T) {
+	type Database struct {
+		Host    string `ini:"host"`
+		Port    int    `ini:"port"`
+		Enabled bool   `ini:"enabled"`
+	}
+	type Config struct {
+		Name     string   `ini:"name"`
+		Database Database `ini:"database"`
+	}
+	input := "name = svc\n\n[database]\n  host = db.local\n  port = 5432\n  enabled = true\n"
+	m, err := mconv.ToMapFromINIE(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var config Config
+	if err := mconv.ToStructE(m, &config); err != nil {
+		t.Fatalf("ToStructE() error = %v", err)
+	}
+	expected := Config{Name: "svc", Database: Database{Host: "db.local", Port: 5432, Enabled: true}}
+	if config != expected {
+		t.Errorf("ToStructE() = %+v; want %+v", config, expected)
+	}
+}
+
+func TestINIErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+	}{
+		{"unterminated section", "[a\nb = 1\n", 1},
+		{"empty section", "a = 1\n[ ]\n", 2},
+		{"missing separator", "[a]\nb\n", 2},
+		{"missing key", "= 1\n", 1},
+		{"section over key", "a = 1\n[a]\n", 2},
+		{"key over section", "[a.b]\n[a]\nb = 1\n", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := mconv.ToMapFromINIE(tt.input)
+			if !errors.Is(err, internal.ErrInvalidINIFormat) {
+				t.Fatalf("error = %v; want ErrInvalidINIFormat", err)
+			}
+			var iniErr *mconv.INIError
+			if !errors.As(err, &iniErr) || iniErr.Line != tt.line {
+				t.Errorf("error = %v; want *INIError at line %d", err, tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package complex_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graingo/mconv/complex"
+)
+
+func TestStreamJSONSeq(t *testing.T) {
+	var names []string
+	var errs int
+	for u, err := range complex.StreamJSONSeq[streamUser](strings.NewReader(`[{"name": "a"}, {"id": "x"}, {"name": "b"}]`)) {
+		if err != nil {
+			errs++
+			continue
+		}
+		names = append(names, u.Name)
+	}
+	if strings.Join(names, ",") != "a,b" || errs != 1 {
+		t.Errorf("names = %v, errors = %d", names, errs)
+	}
+
+	count := 0
+	for range complex.StreamJSONSeq[int](strings.NewReader("1\n2\n3\n")) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("early break: count = %d", count)
+	}
+
+	var last error
+	for _, err := range complex.StreamJSONSeq[int](strings.NewReader(`[1, `)) {
+		last = err
+	}
+	if last == nil {
+		t.Error("expected the final error to be yielded")
+	}
+}
@@ -0,0 +1,206 @@
+package complex_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graingo/mconv"
+	"github.com/graingo/mconv/complex"
+)
+
+type streamUser struct {
+	ID   int64  `json:"id"`
+	Name string `mconv:"name"`
+}
+
+func TestStreamJSONEArray(t *testing.T) {
+	input := ` [{"id": 1, "name": "alice"}, {"id": "2", "name": "bob"}, {"id": "x"}, {"id": 4}] `
+
+	var users []streamUser
+	var failed []int
+	err := complex.StreamJSONE(strings.NewReader(input), func(i int, u streamUser, err error) error {
+		if err != nil {
+			failed = append(failed, i)
+			return nil
+		}
+		users = append(users, u)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []streamUser{{1, "alice"}, {2, "bob"}, {4, ""}}
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("users = %+v; want %+v", users, expected)
+	}
+	if !reflect.DeepEqual(failed, []int{2}) {
+		t.Errorf("failed records = %v; want [2]", failed)
+	}
+}
+
+func TestStreamJSONENDJSON(t *testing.T) {
+	input := "{\"id\": 1}\n{\"id\": 2}\n\n{\"id\": 3}\n"
+
+	var ids []int64
+	err := complex.StreamJSONE(strings.NewReader(input), func(_ int, u *streamUser, err error) error {
+		if err != nil {
+			return err
+		}
+		ids = append(ids, u.ID)
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+		t.Errorf("ids = %v, %v", ids, err)
+	}
+
+	// Arrays as NDJSON records need JSONLines.
+	var rows [][]int
+	err = complex.StreamJSONE(strings.NewReader("[1, 2]\n[3]\n"), func(_ int, row []int, err error) error {
+		rows = append(rows, row)
+		return err
+	}, mconv.JSONLines())
+	if err != nil || !reflect.DeepEqual(rows, [][]int{{1, 2}, {3}}) {
+		t.Errorf("rows = %v, %v", rows, err)
+	}
+
+	// Numbers keep their precision with JSONUseNumber.
+	var values []interface{}
+	err = complex.StreamJSONE(strings.NewReader(`[12345678901234567891]`), func(_ int, v interface{}, err error) error {
+		values = append(values, v)
+		return err
+	}, mconv.JSONUseNumber())
+	if err != nil || !reflect.DeepEqual(values, []interface{}{json.Number("12345678901234567891")}) {
+		t.Errorf("values = %v, %v", values, err)
+	}
+}
+
+func TestStreamJSONENDJSONCorruptLine(t *testing.T) {
+	input := "{\"id\": 1}\n{\"id\": \n\n{\"id\": 3}\n{\"id\": \"x\"}\n{\"id\": 5}"
+
+	var ids []int64
+	var failed []int
+	err := complex.StreamJSONE(strings.NewReader(input), func(i int, u streamUser, err error) error {
+		if err != nil {
+			var jsonErr *complex.JSONError
+			if i == 1 && !errors.As(err, &jsonErr) {
+				t.Errorf("record 1: expected *JSONError, got %v", err)
+			}
+			failed = append(failed, i)
+			return nil
+		}
+		ids = append(ids, u.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 3, 5}) || !reflect.DeepEqual(failed, []int{1, 3}) {
+		t.Errorf("ids = %v, failed = %v; want [1 3 5], [1 3]", ids, failed)
+	}
+
+	// The callback decides whether a malformed line stops the stream.
+	calls := 0
+	err = complex.StreamJSONE(strings.NewReader(input), func(_ int, _ streamUser, err error) error {
+		calls++
+		return err
+	})
+	if err == nil || !strings.Contains(err.Error(), "line 2") || calls != 2 {
+		t.Errorf("error = %v after %d calls; want line 2 error after 2", err, calls)
+	}
+}
+
+// upperName is decoded through its own UnmarshalJSON.
+type upperName string
+
+func (n *upperName) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*n = upperName(strings.ToUpper(s))
+	return nil
+}
+
+func TestStreamJSONEDirectDecoding(t *testing.T) {
+	// 1<<60 itself is exact as float64, so add one to detect rounding.
+	const big = int64(1)<<60 + 1
+	input := fmt.Sprintf(`[{"id": %d, "name": "alice"}]`, big)
+
+	// Integers above 2^53 keep their precision, with and without mconv tags.
+	var tagged []streamUser
+	err := complex.StreamJSONE(strings.NewReader(input), func(_ int, u streamUser, err error) error {
+		tagged = append(tagged, u)
+		return err
+	})
+	if err != nil || len(tagged) != 1 || tagged[0].ID != big {
+		t.Errorf("tagged = %+v, %v; want ID %d", tagged, err, big)
+	}
+
+	type plainUser struct {
+		ID   int64     `json:"id"`
+		Name upperName `json:"name"`
+	}
+	var plain []plainUser
+	err = complex.StreamJSONE(strings.NewReader(input), func(_ int, u plainUser, err error) error {
+		plain = append(plain, u)
+		return err
+	})
+	if err != nil || !reflect.DeepEqual(plain, []plainUser{{big, "ALICE"}}) {
+		t.Errorf("plain = %+v, %v", plain, err)
+	}
+
+	// A target that implements json.Unmarshaler decodes itself.
+	var names []upperName
+	err = complex.StreamJSONE(strings.NewReader(`["bob", 1]`), func(_ int, n upperName, err error) error {
+		if err == nil {
+			names = append(names, n)
+		}
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(names, []upperName{"BOB"}) {
+		t.Errorf("names = %v, %v", names, err)
+	}
+
+	// Values that encoding/json cannot store still convert, and untyped numbers stay float64.
+	var values []map[string]interface{}
+	err = complex.StreamJSONE(strings.NewReader(`{"id": "7", "n": 1.5}`), func(_ int, v map[string]interface{}, err error) error {
+		values = append(values, v)
+		return err
+	})
+	if err != nil || !reflect.DeepEqual(values, []map[string]interface{}{{"id": "7", "n": 1.5}}) {
+		t.Errorf("values = %v, %v", values, err)
+	}
+}
+
+func TestStreamJSONEErrors(t *testing.T) {
+	noop := func(int, map[string]interface{}, error) error { return nil }
+
+	err := complex.StreamJSONE(strings.NewReader(`[{"a": 1}, {"a": }]`), noop)
+	var jsonErr *complex.JSONError
+	if !errors.As(err, &jsonErr) {
+		t.Errorf("expected *JSONError for malformed element, got %v", err)
+	}
+	if err := complex.StreamJSONE(strings.NewReader(`[{"a": 1}`), noop); err == nil {
+		t.Error("expected error for unterminated array")
+	}
+	if err := complex.StreamJSONE(strings.NewReader(`[] x`), noop); err == nil {
+		t.Error("expected error for trailing data")
+	}
+	if err := complex.StreamJSONE(strings.NewReader("   "), noop); err != nil {
+		t.Errorf("empty input: unexpected error %v", err)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = complex.StreamJSONE(strings.NewReader(`[1, 2, 3]`), func(int, int, error) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("callback error = %v after %d calls; want stop after 1", err, calls)
+	}
+}
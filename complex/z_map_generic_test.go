@@ -2,7 +2,9 @@ package complex_test
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/graingo/mconv/complex"
 )
@@ -72,3 +74,60 @@ func TestToMapTE(t *testing.T) {
 		}
 	})
 }
+
+func TestToMapTEEngine(t *testing.T) {
+	t.Run("uint32 keys and durations", func(t *testing.T) {
+		source := map[string]interface{}{"1": "1s", "2": 1500}
+		got, err := complex.ToMapTE[uint32, time.Duration](source)
+		expected := map[uint32]time.Duration{1: time.Second, 2: 1500}
+		if err != nil || !reflect.DeepEqual(got, expected) {
+			t.Errorf("ToMapTE[uint32, time.Duration] = %v, %v", got, err)
+		}
+	})
+
+	t.Run("original key types", func(t *testing.T) {
+		got, err := complex.ToMapTE[float64, string](map[float64]int{1.5: 1})
+		if err != nil || !reflect.DeepEqual(got, map[float64]string{1.5: "1"}) {
+			t.Errorf("ToMapTE[float64, string] = %v, %v", got, err)
+		}
+	})
+
+	t.Run("string slices", func(t *testing.T) {
+		source := map[string]interface{}{"Accept": []interface{}{"a", "b"}, "X-Id": "1"}
+		got, err := complex.ToMapTE[string, []string](source)
+		expected := map[string][]string{"Accept": {"a", "b"}, "X-Id": {"1"}}
+		if err != nil || !reflect.DeepEqual(got, expected) {
+			t.Errorf("ToMapTE[string, []string] = %v, %v", got, err)
+		}
+	})
+
+	t.Run("structs", func(t *testing.T) {
+		type Config struct {
+			Host string `json:"host"`
+			Port int    `json:"port"`
+		}
+		source := map[string]interface{}{
+			"primary": map[string]interface{}{"host": "a", "port": "80"},
+			"replica": map[string]string{"host": "b", "port": "81"},
+		}
+		got, err := complex.ToMapTE[string, Config](source)
+		expected := map[string]Config{"primary": {"a", 80}, "replica": {"b", 81}}
+		if err != nil || !reflect.DeepEqual(got, expected) {
+			t.Errorf("ToMapTE[string, Config] = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("int values are not treated as runes", func(t *testing.T) {
+		got, err := complex.ToMapTE[string, string](map[string]int{"a": 65})
+		if err != nil || got["a"] != "65" {
+			t.Errorf("ToMapTE[string, string] = %v, %v", got, err)
+		}
+	})
+
+	t.Run("error names the key", func(t *testing.T) {
+		_, err := complex.ToMapTE[string, int](map[string]interface{}{"port": "x"})
+		if err == nil || !strings.Contains(err.Error(), "key port") {
+			t.Errorf("expected error naming key port, got %v", err)
+		}
+	})
+}
@@ -1,7 +1,12 @@
 package complex_test
 
 import (
+	"math/big"
+	"net/http"
+	"net/url"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/graingo/mconv"
 )
@@ -50,6 +55,11 @@ func TestToMapE(t *testing.T) {
 		{map[interface{}]interface{}{"a": 1, "b": 2}, map[string]interface{}{"a": 1, "b": 2}, false},
 		{123, nil, true},
 		{nil, nil, false},
+		// Structs that encode themselves or have no exported fields are not maps.
+		{time.Now(), nil, true},
+		{mconv.NewDecimal(5, 1), nil, true},
+		{big.NewInt(5), nil, true},
+		{struct{ secret int }{1}, nil, true},
 	}
 
 	for _, test := range tests {
@@ -74,6 +84,27 @@ func TestToMapE(t *testing.T) {
 	}
 }
 
+func TestOpaqueStructsAreNotMaps(t *testing.T) {
+	inputs := []interface{}{time.Time{}, mconv.NewDecimal(5, 1), big.NewInt(5)}
+	for _, input := range inputs {
+		if _, err := mconv.ToStringMapE(input); err == nil {
+			t.Errorf("mconv.ToStringMapE(%T) expected error", input)
+		}
+		if _, err := mconv.ToIntMapE(input); err == nil {
+			t.Errorf("mconv.ToIntMapE(%T) expected error", input)
+		}
+		if _, err := mconv.ToFloat64MapE(input); err == nil {
+			t.Errorf("mconv.ToFloat64MapE(%T) expected error", input)
+		}
+		var cfg struct {
+			Name string `mconv:"name"`
+		}
+		if err := mconv.ToStructE(input, &cfg); err == nil {
+			t.Errorf("mconv.ToStructE(%T) expected error", input)
+		}
+	}
+}
+
 func TestToStringMap(t *testing.T) {
 	tests := []struct {
 		input    interface{}
@@ -322,3 +353,74 @@ func float64MapEqual(a, b map[string]float64) bool {
 	}
 	return true
 }
+
+func TestToStringMapEAnyMap(t *testing.T) {
+	got, err := mconv.ToStringMapE(map[string]int{"a": 1})
+	if err != nil || !reflect.DeepEqual(got, map[string]string{"a": "1"}) {
+		t.Errorf("mconv.ToStringMapE(map[string]int) = %v, %v", got, err)
+	}
+
+	type Server struct {
+		Host string `json:"host"`
+		Port int    `mconv:"port"`
+	}
+	got, err = mconv.ToStringMapE(&Server{Host: "localhost", Port: 80})
+	if err != nil || !reflect.DeepEqual(got, map[string]string{"host": "localhost", "port": "80"}) {
+		t.Errorf("mconv.ToStringMapE(struct) = %v, %v", got, err)
+	}
+}
+
+func TestTypedMaps(t *testing.T) {
+	if got := mconv.ToInt64Map(map[string]string{"a": "1"}); !reflect.DeepEqual(got, map[string]int64{"a": 1}) {
+		t.Errorf("mconv.ToInt64Map = %v", got)
+	}
+	if got := mconv.ToBoolMap(map[string]interface{}{"a": "yes", "b": 0}); !reflect.DeepEqual(got, map[string]bool{"a": true, "b": false}) {
+		t.Errorf("mconv.ToBoolMap = %v", got)
+	}
+	if got := mconv.ToDurationMap(map[string]string{"timeout": "5s"}); !reflect.DeepEqual(got, map[string]time.Duration{"timeout": 5 * time.Second}) {
+		t.Errorf("mconv.ToDurationMap = %v", got)
+	}
+
+	header := http.Header{"Accept": {"text/plain", "text/html"}}
+	if got := mconv.ToStringMapStringSlice(header); !reflect.DeepEqual(got, map[string][]string(header)) {
+		t.Errorf("mconv.ToStringMapStringSlice(http.Header) = %v", got)
+	}
+	values := url.Values{"q": {"go"}}
+	if got := mconv.ToStringMapStringSlice(values); !reflect.DeepEqual(got, map[string][]string(values)) {
+		t.Errorf("mconv.ToStringMapStringSlice(url.Values) = %v", got)
+	}
+	mixed := map[string]interface{}{"a": "x", "b": []interface{}{1, "2"}}
+	if got := mconv.ToStringMapStringSlice(mixed); !reflect.DeepEqual(got, map[string][]string{"a": {"x"}, "b": {"1", "2"}}) {
+		t.Errorf("mconv.ToStringMapStringSlice(mixed) = %v", got)
+	}
+
+	type DB struct {
+		Host string `json:"host"`
+	}
+	nested := map[string]interface{}{
+		"db":    DB{Host: "x"},
+		"cache": map[string]interface{}{"ttl": 5},
+	}
+	expected := map[string]map[string]interface{}{
+		"db":    {"host": "x"},
+		"cache": {"ttl": 5},
+	}
+	if got := mconv.ToNestedMap(nested); !reflect.DeepEqual(got, expected) {
+		t.Errorf("mconv.ToNestedMap = %v", got)
+	}
+}
+
+func TestTypedMapsE(t *testing.T) {
+	if _, err := mconv.ToInt64MapE(map[string]string{"a": "x"}); err == nil {
+		t.Error("mconv.ToInt64MapE expected error")
+	}
+	if _, err := mconv.ToBoolMapE("not a map"); err == nil {
+		t.Error("mconv.ToBoolMapE expected error")
+	}
+	if _, err := mconv.ToDurationMapE(map[string]string{"a": "soon"}); err == nil {
+		t.Error("mconv.ToDurationMapE expected error")
+	}
+	if _, err := mconv.ToNestedMapE(map[string]interface{}{"a": 1}); err == nil {
+		t.Error("mconv.ToNestedMapE expected error")
+	}
+}
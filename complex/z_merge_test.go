@@ -0,0 +1,261 @@
+package complex_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graingo/mconv/complex"
+)
+
+func TestMergeEMaps(t *testing.T) {
+	dst := map[string]interface{}{
+		"name": "app",
+		"db":   map[string]interface{}{"host": "localhost", "port": 5432},
+		"tags": []string{"a"},
+	}
+	file := map[string]interface{}{
+		"db":   map[string]string{"host": "db.internal"},
+		"tags": []string{"b"},
+	}
+	env := map[string]interface{}{
+		"db":   map[string]interface{}{"port": 6543, "user": nil},
+		"name": "",
+	}
+
+	if err := complex.MergeE(dst, file, env); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The empty name is present in env and replaces the earlier value; nil is skipped.
+	expected := map[string]interface{}{
+		"name": "",
+		"db":   map[string]interface{}{"host": "db.internal", "port": 6543},
+		"tags": []string{"b"},
+	}
+	if !reflect.DeepEqual(dst, expected) {
+		t.Errorf("MergeE = %v; want %v", dst, expected)
+	}
+}
+
+func TestMergeEStructs(t *testing.T) {
+	type DB struct {
+		Host    string        `json:"host"`
+		Port    int           `json:"port"`
+		Timeout time.Duration `json:"timeout"`
+	}
+	type Config struct {
+		Name  string   `json:"name"`
+		Debug bool     `json:"debug"`
+		DB    DB       `json:"db"`
+		Tags  []string `json:"tags"`
+	}
+
+	cfg := Config{Name: "default", DB: DB{Host: "localhost", Port: 5432, Timeout: time.Second}}
+	fileLayer := Config{DB: DB{Host: "db.internal"}, Tags: []string{"file"}}
+	envLayer := map[string]interface{}{"debug": "true", "db": map[string]interface{}{"port": "6543"}}
+
+	// Environment layers hold strings, which ToStructE converts to the field types.
+	opts := complex.MergeOptions{AllowTypeChange: true}
+	if _, err := complex.MergeWithOptionsE(&cfg, opts, fileLayer, envLayer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Config{
+		Name:  "default",
+		Debug: true,
+		DB:    DB{Host: "db.internal", Port: 6543, Timeout: time.Second},
+		Tags:  []string{"file"},
+	}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("MergeE = %+v; want %+v", cfg, expected)
+	}
+}
+
+func TestMergeELayeredZeroValues(t *testing.T) {
+	type Config struct {
+		Debug bool   `json:"debug"`
+		Level int    `json:"level"`
+		Name  string `json:"name"`
+	}
+
+	// Explicit zero values in later map layers turn earlier settings off.
+	cfg := Config{Debug: true, Level: 3, Name: "app"}
+	fileLayer := map[string]interface{}{"debug": true, "level": 2}
+	envLayer := map[string]interface{}{"debug": false, "level": 0}
+	if err := complex.MergeE(&cfg, fileLayer, envLayer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (Config{Name: "app"}); cfg != expected {
+		t.Errorf("MergeE = %+v; want %+v", cfg, expected)
+	}
+
+	// Zero struct fields mean unset and are skipped unless OverwriteWithEmpty is set.
+	cfg = Config{Debug: true, Level: 3, Name: "app"}
+	if err := complex.MergeE(&cfg, Config{Level: 4}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (Config{Debug: true, Level: 4, Name: "app"}); cfg != expected {
+		t.Errorf("MergeE(struct) = %+v; want %+v", cfg, expected)
+	}
+	if _, err := complex.MergeWithOptionsE(&cfg, complex.MergeOptions{OverwriteWithEmpty: true}, Config{Level: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (Config{Level: 5}); cfg != expected {
+		t.Errorf("MergeWithOptionsE(OverwriteWithEmpty) = %+v; want %+v", cfg, expected)
+	}
+}
+
+func TestMergeWithOptionsESlices(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     complex.MergeOptions
+		expected interface{}
+	}{
+		{"replace", complex.MergeOptions{}, []int{2, 3}},
+		{"append", complex.MergeOptions{Slices: complex.SliceAppend}, []interface{}{1, 2, 2, 3}},
+		{"unique", complex.MergeOptions{Slices: complex.SliceAppendUnique}, []interface{}{1, 2, 3}},
+	}
+	for _, test := range tests {
+		dst := map[string]interface{}{"v": []int{1, 2}}
+		if _, err := complex.MergeWithOptionsE(dst, test.opts, map[string]interface{}{"v": []int{2, 3}}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(dst["v"], test.expected) {
+			t.Errorf("%s: got %v; want %v", test.name, dst["v"], test.expected)
+		}
+	}
+
+	dst := map[string]interface{}{
+		"users": []interface{}{
+			map[string]interface{}{"id": 1, "name": "alice", "role": "admin"},
+			map[string]interface{}{"id": 2, "name": "bob"},
+		},
+	}
+	src := map[string]interface{}{
+		"users": []map[string]interface{}{
+			{"id": "2", "name": "robert"},
+			{"id": 3, "name": "carol"},
+		},
+	}
+	opts := complex.MergeOptions{Slices: complex.SliceMergeByKey, MergeKey: "id"}
+	if _, err := complex.MergeWithOptionsE(dst, opts, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"id": 1, "name": "alice", "role": "admin"},
+		map[string]interface{}{"id": "2", "name": "robert"},
+		map[string]interface{}{"id": 3, "name": "carol"},
+	}
+	if !reflect.DeepEqual(dst["users"], expected) {
+		t.Errorf("merge by key = %v; want %v", dst["users"], expected)
+	}
+}
+
+func TestMergeWithOptionsEEmptyValues(t *testing.T) {
+	dst := map[string]interface{}{"a": "x", "b": 1}
+	src := map[string]interface{}{"a": "", "b": nil}
+
+	if _, err := complex.MergeWithOptionsE(dst, complex.MergeOptions{}, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst["a"] != "" || dst["b"] != 1 {
+		t.Errorf("zero values should overwrite and nil should be skipped by default, got %v", dst)
+	}
+
+	if _, err := complex.MergeWithOptionsE(dst, complex.MergeOptions{OverwriteWithEmpty: true}, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst["a"] != "" || dst["b"] != nil {
+		t.Errorf("empty values should overwrite, got %v", dst)
+	}
+}
+
+func TestMergeWithOptionsEProvenance(t *testing.T) {
+	dst := map[string]interface{}{"name": "app", "db": map[string]interface{}{"host": "localhost", "port": 1}}
+	srcs := []interface{}{
+		map[string]interface{}{"db": map[string]interface{}{"host": "file"}},
+		map[string]interface{}{"db": map[string]interface{}{"port": 2}, "debug": true},
+	}
+	provenance, err := complex.MergeWithOptionsE(dst, complex.MergeOptions{}, srcs...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]int{"name": -1, "db.host": 0, "db.port": 1, "debug": 1}
+	if !reflect.DeepEqual(provenance, expected) {
+		t.Errorf("provenance = %v; want %v", provenance, expected)
+	}
+}
+
+func TestMergeENoSources(t *testing.T) {
+	dst := map[string]interface{}{"name": "app", "port": 8080}
+	expected := map[string]interface{}{"name": "app", "port": 8080}
+	if err := complex.MergeE(dst); err != nil || !reflect.DeepEqual(dst, expected) {
+		t.Errorf("MergeE() = %v, %v; want %v", dst, err, expected)
+	}
+	if err := complex.MergeE(dst, nil, nil); err != nil || !reflect.DeepEqual(dst, expected) {
+		t.Errorf("MergeE(nil, nil) = %v, %v; want %v", dst, err, expected)
+	}
+	if err := complex.MergeE(dst, map[string]interface{}{"name": nil}); err != nil || !reflect.DeepEqual(dst, expected) {
+		t.Errorf("MergeE with only nil values = %v, %v; want %v", dst, err, expected)
+	}
+}
+
+func TestMergeETypeConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  map[string]interface{}
+		src  map[string]interface{}
+	}{
+		{"string over int", map[string]interface{}{"port": 8080}, map[string]interface{}{"port": "8081"}},
+		{"scalar over slice", map[string]interface{}{"hosts": []string{"a"}}, map[string]interface{}{"hosts": "b"}},
+		{"slice over scalar", map[string]interface{}{"host": "a"}, map[string]interface{}{"host": []string{"b"}}},
+		{"bool over string", map[string]interface{}{"mode": "on"}, map[string]interface{}{"mode": true}},
+	}
+	for _, tt := range tests {
+		err := complex.MergeE(tt.dst, tt.src)
+		if err == nil || !strings.Contains(err.Error(), "cannot merge") {
+			t.Errorf("%s: expected type conflict error, got %v", tt.name, err)
+		}
+		opts := complex.MergeOptions{AllowTypeChange: true}
+		if _, err := complex.MergeWithOptionsE(tt.dst, opts, tt.src); err != nil {
+			t.Errorf("%s: unexpected error with AllowTypeChange: %v", tt.name, err)
+		}
+		for k, v := range tt.src {
+			if !reflect.DeepEqual(tt.dst[k], v) {
+				t.Errorf("%s: %s = %v; want %v", tt.name, k, tt.dst[k], v)
+			}
+		}
+	}
+
+	// Numbers of different types do not conflict.
+	dst := map[string]interface{}{"ratio": 1}
+	if err := complex.MergeE(dst, map[string]interface{}{"ratio": 0.5}); err != nil || dst["ratio"] != 0.5 {
+		t.Errorf("MergeE number = %v, %v; want 0.5", dst["ratio"], err)
+	}
+}
+
+func TestMergeEErrors(t *testing.T) {
+	dst := map[string]interface{}{"db": map[string]interface{}{"host": "x"}}
+	err := complex.MergeE(dst, map[string]interface{}{"db": "postgres://"})
+	if err == nil || !strings.Contains(err.Error(), `"db"`) {
+		t.Errorf("expected type conflict error for db, got %v", err)
+	}
+
+	if err := complex.MergeE(nil, map[string]interface{}{}); err == nil {
+		t.Error("expected error for nil destination")
+	}
+	var nilMap map[string]interface{}
+	if err := complex.MergeE(nilMap); err == nil {
+		t.Error("expected error for nil destination map")
+	}
+	if err := complex.MergeE(&nilMap, map[string]interface{}{"a": 1}); err != nil || nilMap["a"] != 1 {
+		t.Errorf("MergeE into pointer to nil map = %v, %v", nilMap, err)
+	}
+	if err := complex.MergeE(map[string]interface{}{}, 42); err == nil {
+		t.Error("expected error for non-map source")
+	}
+	opts := complex.MergeOptions{Slices: complex.SliceMergeByKey}
+	if _, err := complex.MergeWithOptionsE(map[string]interface{}{"a": []int{1}}, opts, map[string]interface{}{"a": []int{2}}); err == nil {
+		t.Error("expected error for SliceMergeByKey without MergeKey")
+	}
+}
@@ -0,0 +1,202 @@
+package complex_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/graingo/mconv"
+	"github.com/graingo/mconv/complex"
+)
+
+func TestApplyMergePatchJSON(t *testing.T) {
+	// Test cases from RFC 7386, Appendix A.
+	tests := []struct {
+		doc, patch, expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"n":12345678901234567890}`, `{"m":1.50}`, `{"m":1.50,"n":12345678901234567890}`},
+	}
+	for _, tt := range tests {
+		result, err := complex.ApplyMergePatchJSON(tt.doc, tt.patch)
+		if err != nil || result != tt.expected {
+			t.Errorf("ApplyMergePatchJSON(%s, %s) = %s, %v; want %s", tt.doc, tt.patch, result, err, tt.expected)
+		}
+	}
+	if _, err := complex.ApplyMergePatchJSON(`{`, `{}`); err == nil {
+		t.Error("expected error for invalid document")
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	doc := mconv.ToMapFromJSON(`{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"]}`)
+	patch := map[string]interface{}{
+		"title":       "Hello!",
+		"phoneNumber": "+01-123-456-7890",
+		"author":      map[string]interface{}{"familyName": nil},
+		"tags":        []string{"example"},
+	}
+	result, err := complex.ApplyMergePatch(doc, patch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"title":       "Hello!",
+		"author":      map[string]interface{}{"givenName": "John"},
+		"tags":        []interface{}{"example"},
+		"phoneNumber": "+01-123-456-7890",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ApplyMergePatch() = %v; want %v", result, expected)
+	}
+	// The document is not modified.
+	if doc["title"] != "Goodbye!" || len(doc["author"].(map[string]interface{})) != 2 {
+		t.Errorf("document was modified: %v", doc)
+	}
+
+	// Structs are read with their ToStructE field names.
+	type settings struct {
+		Theme string `json:"theme"`
+		Size  int    `json:"size"`
+	}
+	result, _ = complex.ApplyMergePatch(settings{Theme: "dark", Size: 12}, `ignored`)
+	if result != "ignored" {
+		t.Errorf("scalar patch should replace the document, got %v", result)
+	}
+	result, _ = complex.ApplyMergePatch(&settings{Theme: "dark", Size: 12}, map[string]interface{}{"size": 14})
+	var patched settings
+	if err := mconv.ToStructE(result, &patched); err != nil || patched != (settings{Theme: "dark", Size: 14}) {
+		t.Errorf("patched struct = %+v, %v", patched, err)
+	}
+}
+
+func TestApplyJSONPatchJSON(t *testing.T) {
+	// Test cases from RFC 6902, Appendix A.
+	tests := []struct {
+		doc, patch, expected string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"child":{"grandchild":{}},"foo":"bar"}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`},
+		{`{"foo":null}`, `[{"op":"add","path":"/foo","value":null}]`, `{"foo":null}`},
+		{`{"a":{"b":[1]}}`, `[{"op":"copy","from":"/a/b","path":"/c"},{"op":"add","path":"/c/-","value":2}]`, `{"a":{"b":[1]},"c":[1,2]}`},
+		{`{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+		{`{"a":1.0}`, `[{"op":"test","path":"/a","value":1}]`, `{"a":1.0}`},
+	}
+	for _, tt := range tests {
+		result, err := complex.ApplyJSONPatchJSON(tt.doc, tt.patch)
+		if err != nil || result != tt.expected {
+			t.Errorf("ApplyJSONPatchJSON(%s, %s) = %s, %v; want %s", tt.doc, tt.patch, result, err, tt.expected)
+		}
+	}
+
+	errorTests := []struct {
+		doc, patch string
+		target     error
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, complex.ErrPathNotFound},
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, complex.ErrPatchTestFailed},
+		{`{"foo":["bar"]}`, `[{"op":"remove","path":"/foo/1"}]`, complex.ErrPathNotFound},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`, complex.ErrPathNotFound},
+		{`{"foo":"bar"}`, `[{"op":"test","path":"/foo","value":"bar"},{"op":"test","path":"/foo","value":1}]`, complex.ErrPatchTestFailed},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/a"}]`, nil},
+		{`{"foo":"bar"}`, `[{"op":"frob","path":"/a"}]`, nil},
+		{`{"foo":"bar"}`, `[{"op":"move","from":"/foo","path":"/foo/x"}]`, nil},
+		{`{"foo":[1]}`, `[{"op":"add","path":"/foo/2","value":1}]`, nil},
+		{`{"foo":"bar"}`, `{"op":"add"}`, nil},
+	}
+	for _, tt := range errorTests {
+		_, err := complex.ApplyJSONPatchJSON(tt.doc, tt.patch)
+		if err == nil || (tt.target != nil && !errors.Is(err, tt.target)) {
+			t.Errorf("ApplyJSONPatchJSON(%s, %s) error = %v; want %v", tt.doc, tt.patch, err, tt.target)
+		}
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	doc := mconv.ToMapFromJSON(`{"name":"app","ports":[80,443]}`)
+	ops := []complex.PatchOperation{
+		{Op: "replace", Path: "/name", Value: "web"},
+		{Op: "add", Path: "/ports/0", Value: 8080},
+		{Op: "test", Path: "/ports/2", Value: 443},
+		{Op: "remove", Path: "/ports/1"},
+	}
+	result, err := complex.ApplyJSONPatch(doc, ops)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{"name": "web", "ports": []interface{}{8080, float64(443)}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ApplyJSONPatch() = %v; want %v", result, expected)
+	}
+	if doc["name"] != "app" || len(doc["ports"].([]interface{})) != 2 {
+		t.Errorf("document was modified: %v", doc)
+	}
+
+	// A failing operation leaves no partial result.
+	result, err = complex.ApplyJSONPatch(doc, []complex.PatchOperation{{Op: "remove", Path: "/name"}, {Op: "remove", Path: "/missing"}})
+	if err == nil || result != nil {
+		t.Errorf("ApplyJSONPatch() = %v, %v; want error", result, err)
+	}
+	if _, ok := doc["name"]; !ok {
+		t.Error("document was modified by a failed patch")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := `{"name":"app","ports":[80,443,8443],"meta":{"a/b":1,"old":true},"n":1}`
+	b := `{"name":"web","ports":[80,444],"meta":{"a/b":1,"new":null},"n":1.0,"extra":[]}`
+
+	patch, err := complex.DiffJSON(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `[{"op":"add","path":"/extra","value":[]},{"op":"add","path":"/meta/new","value":null},` +
+		`{"op":"remove","path":"/meta/old"},{"op":"replace","path":"/name","value":"web"},` +
+		`{"op":"replace","path":"/ports/1","value":444},{"op":"remove","path":"/ports/2"}]`
+	if patch != expected {
+		t.Errorf("DiffJSON() = %s; want %s", patch, expected)
+	}
+
+	// Applying the diff turns a into b.
+	result, err := complex.ApplyJSONPatchJSON(a, patch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff, _ := complex.DiffJSON(result, b); diff != "[]" {
+		t.Errorf("patched document differs from target: %s", diff)
+	}
+
+	ops, err := complex.Diff(map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1.0})
+	if err != nil || len(ops) != 0 {
+		t.Errorf("Diff(equal numbers) = %v, %v; want no operations", ops, err)
+	}
+	ops, _ = complex.Diff([]int{1}, "x")
+	if len(ops) != 1 || ops[0].Op != "replace" || ops[0].Path != "" {
+		t.Errorf("Diff(root) = %v", ops)
+	}
+}
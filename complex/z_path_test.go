@@ -0,0 +1,154 @@
+package complex_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graingo/mconv/complex"
+)
+
+func TestGet(t *testing.T) {
+	data, err := complex.ToMapFromJSONE(`{
+		"users": [
+			{"name": "alice", "age": "30", "joined": "2024-01-02T03:04:05Z",
+			 "address": {"city": "Paris"}, "ttl": "1m", "admin": "yes"}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := complex.Get(data, "users.0.address.city").String(); got != "Paris" {
+		t.Errorf("Get city = %q", got)
+	}
+	if got := complex.Get(data, "users[0].Address.City").String(); got != "Paris" {
+		t.Errorf("Get with index and case-insensitive keys = %q", got)
+	}
+	if got := complex.Get(data, "users.0.age").Int(); got != 30 {
+		t.Errorf("Get age = %d", got)
+	}
+	if got := complex.Get(data, "users.0.joined").Time(); got.Unix() != 1704164645 {
+		t.Errorf("Get joined = %v", got)
+	}
+	if got := complex.Get(data, "users.0.ttl").Duration(); got != time.Minute {
+		t.Errorf("Get ttl = %v", got)
+	}
+	if !complex.Get(data, "users.0.admin").Bool() {
+		t.Error("Get admin = false")
+	}
+	if v := complex.Get(data, "users.1.name"); !v.IsNil() {
+		t.Errorf("Get missing = %v", v.Interface())
+	}
+	if _, err := complex.GetE(data, "users.0.phone"); !errors.Is(err, complex.ErrPathNotFound) {
+		t.Errorf("GetE missing error = %v", err)
+	}
+	if _, err := complex.GetE(data, "users[x]"); err == nil {
+		t.Error("expected error for malformed path")
+	}
+	if v, err := complex.GetE(data, ""); err != nil || !reflect.DeepEqual(v.Interface(), data) {
+		t.Errorf("GetE empty path = %v, %v", v, err)
+	}
+
+	type Address struct {
+		City string `json:"city"`
+	}
+	type User struct {
+		Addresses []Address `json:"addresses"`
+	}
+	user := &User{Addresses: []Address{{City: "Rome"}}}
+	if got := complex.Get(user, "addresses.0.city").String(); got != "Rome" {
+		t.Errorf("Get on struct = %q", got)
+	}
+}
+
+func TestSet(t *testing.T) {
+	data := map[string]interface{}{}
+	if err := complex.Set(data, "users.1.address.city", "Paris"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"users": []interface{}{
+			nil,
+			map[string]interface{}{"address": map[string]interface{}{"city": "Paris"}},
+		},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Set = %v; want %v", data, expected)
+	}
+
+	if err := complex.Set(data, "users[0]", "first"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := complex.Get(data, "users.0").String(); got != "first" {
+		t.Errorf("Set users[0] = %q", got)
+	}
+
+	var list []interface{}
+	if err := complex.Set(&list, "2", 3); err != nil || !reflect.DeepEqual(list, []interface{}{nil, nil, 3}) {
+		t.Errorf("Set on slice pointer = %v, %v", list, err)
+	}
+
+	var root interface{}
+	if err := complex.Set(&root, "a.b", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(root, map[string]interface{}{"a": map[string]interface{}{"b": true}}) {
+		t.Errorf("Set on interface pointer = %v", root)
+	}
+
+	type DB struct {
+		Port int `json:"port"`
+	}
+	type Config struct {
+		DB    *DB               `json:"db"`
+		Tags  []string          `json:"tags"`
+		Extra map[string]string `json:"extra"`
+	}
+	var cfg Config
+	if err := complex.Set(&cfg, "db.port", "5432"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := complex.Set(&cfg, "tags.1", 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := complex.Set(&cfg, "extra.mode", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DB == nil || cfg.DB.Port != 5432 || !reflect.DeepEqual(cfg.Tags, []string{"", "7"}) || cfg.Extra["mode"] != "1" {
+		t.Errorf("Set on struct = %+v", cfg)
+	}
+
+	if err := complex.Set(&cfg, "missing", 1); err == nil {
+		t.Error("expected error for unknown struct field")
+	}
+	if err := complex.Set(map[string]interface{}{"a": 1}, "a.b", 1); err == nil {
+		t.Error("expected error when traversing a scalar")
+	}
+	if err := complex.Set(data, "", 1); err == nil {
+		t.Error("expected error for empty path")
+	}
+	if err := complex.Set([]interface{}{}, "0", 1); err == nil {
+		t.Error("expected error for non-pointer slice")
+	}
+
+	// Slices grow by a bounded number of elements.
+	huge := map[string]interface{}{}
+	if err := complex.Set(huge, "items.999999999", 1); err == nil {
+		t.Error("expected error for an index far past the end")
+	}
+	if _, ok := huge["items"]; ok {
+		t.Errorf("failed Set modified the map: %v", huge)
+	}
+	var grown []interface{}
+	if err := complex.Set(&grown, "1023", 1); err != nil || len(grown) != 1024 {
+		t.Errorf("Set(1023) = %v, len %d; want len 1024", err, len(grown))
+	}
+	if err := complex.Set(&grown, "2047", 1); err != nil || len(grown) != 2048 {
+		t.Errorf("Set(2047) = %v, len %d; want len 2048", err, len(grown))
+	}
+	if err := complex.Set(&grown, "3072", 1); err == nil || len(grown) != 2048 {
+		t.Errorf("Set(3072) = %v, len %d; want error", err, len(grown))
+	}
+}
@@ -0,0 +1,169 @@
+package complex_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/graingo/mconv"
+	"github.com/graingo/mconv/complex"
+)
+
+const queryDocument = `{
+	"store": {
+		"users": [
+			{"name": "alice", "age": 34, "email": "a@example.com", "tags": ["admin"]},
+			{"name": "bob", "age": 28},
+			{"name": "carol", "age": 41, "email": "c@example.com", "tags": ["ops", "admin"]}
+		],
+		"limits": {"age": 30, "max": 100},
+		"a/b": 1,
+		"m~n": 2,
+		"": "empty"
+	}
+}`
+
+func queryStrings(vars []*complex.Var) []string {
+	result := make([]string, len(vars))
+	for i, v := range vars {
+		result[i] = v.String()
+	}
+	return result
+}
+
+func TestGetPointer(t *testing.T) {
+	data := mconv.ToMapFromJSON(queryDocument, mconv.JSONUseNumber())
+
+	tests := []struct {
+		pointer  string
+		expected string
+	}{
+		{"/store/users/0/name", "alice"},
+		{"/store/users/2/tags/1", "admin"},
+		{"/store/a~1b", "1"},
+		{"/store/m~0n", "2"},
+		{"/store/", "empty"},
+		{"/store/limits/age", "30"},
+	}
+	for _, tt := range tests {
+		v, err := complex.GetPointerE(data, tt.pointer)
+		if err != nil || v.String() != tt.expected {
+			t.Errorf("GetPointerE(%q) = %v, %v; want %v", tt.pointer, v, err, tt.expected)
+		}
+	}
+
+	if v := complex.GetPointer(data, "/store/users/1/age"); v.Int() != 28 {
+		t.Errorf("GetPointer age = %d; want 28", v.Int())
+	}
+	if v, err := complex.GetPointerE(data, ""); err != nil || !reflect.DeepEqual(v.Interface(), data) {
+		t.Errorf("empty pointer should reference the whole document, got %v, %v", v, err)
+	}
+
+	// Pointers match keys exactly and reject malformed indices.
+	for _, pointer := range []string{"/Store", "/store/users/01", "/store/users/-", "/store/users/3", "/store/users/0/name/x"} {
+		if _, err := complex.GetPointerE(data, pointer); !errors.Is(err, complex.ErrPathNotFound) {
+			t.Errorf("GetPointerE(%q) error = %v; want ErrPathNotFound", pointer, err)
+		}
+	}
+	for _, pointer := range []string{"store", "/a~2", "/a~"} {
+		if _, err := complex.GetPointerE(data, pointer); err == nil || errors.Is(err, complex.ErrPathNotFound) {
+			t.Errorf("GetPointerE(%q) error = %v; want invalid pointer", pointer, err)
+		}
+	}
+	if v := complex.GetPointer(data, "/missing"); !v.IsNil() {
+		t.Errorf("GetPointer(missing) = %v; want nil", v.Interface())
+	}
+
+	// Structs are addressed by their ToStructE field names.
+	type user struct {
+		Name  string `json:"name"`
+		Roles []string
+	}
+	if v := complex.GetPointer(&user{Name: "dave", Roles: []string{"x", "y"}}, "/Roles/1"); v.String() != "y" {
+		t.Errorf("GetPointer(struct) = %v", v.Interface())
+	}
+}
+
+func TestQuery(t *testing.T) {
+	data := mconv.ToMapFromJSON(queryDocument, mconv.JSONUseNumber())
+
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{"$.store.users[*].name", []string{"alice", "bob", "carol"}},
+		{"$['store']['users'][0]['name']", []string{"alice"}},
+		{"$.store.users[-1].name", []string{"carol"}},
+		{"$.store.users[0,2].name", []string{"alice", "carol"}},
+		{"$.store.users[1:].name", []string{"bob", "carol"}},
+		{"$.store.users[::-1].name", []string{"carol", "bob", "alice"}},
+		{"$.store.users[?(@.age>30)].name", []string{"alice", "carol"}},
+		{"$.store.users[?@.age >= 28 && @.age < 40].name", []string{"alice", "bob"}},
+		{"$.store.users[?(@.name == 'bob' || @.age > 40)].name", []string{"bob", "carol"}},
+		{"$.store.users[?(@.email)].name", []string{"alice", "carol"}},
+		{"$.store.users[?(!@.email)].name", []string{"bob"}},
+		{"$.store.users[?(@.tags[0] == 'ops')].name", []string{"carol"}},
+		{"$.store.users[?(@.age > $.store.limits.age)].name", []string{"alice", "carol"}},
+		{"$.store.users[?(@.age == '34')].name", nil},
+		{"$.store.users[?(@.email != null)].name", []string{"alice", "bob", "carol"}},
+		{"$..tags[*]", []string{"admin", "ops", "admin"}},
+		{"$.store.limits.*", []string{"30", "100"}},
+		{"$..missing", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, err := complex.QueryE(data, tt.expr)
+			if err != nil {
+				t.Fatalf("QueryE() unexpected error: %v", err)
+			}
+			got := queryStrings(result)
+			if len(got) == 0 && len(tt.expected) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("QueryE() = %v; want %v", got, tt.expected)
+			}
+		})
+	}
+
+	// Results convert with the typed accessors.
+	ages := complex.Query(data, "$.store.users[*].age")
+	if len(ages) != 3 || ages[0].Int() != 34 || ages[2].Float64() != 41 {
+		t.Errorf("Query ages = %v", queryStrings(ages))
+	}
+	if names := complex.Query(data, "$.store.users[?(@.age > 30)]"); len(names) != 2 || names[1].Map()["name"] != "carol" {
+		t.Errorf("Query filtered users = %v", names)
+	}
+
+	for _, expr := range []string{"", "store.users", "$.store[", "$.store.users[?(@.age >)]", "$['unterminated]", "$.store.users[::0]", "$.a b"} {
+		if _, err := complex.QueryE(data, expr); err == nil {
+			t.Errorf("QueryE(%q) expected error", expr)
+		}
+	}
+	if result := complex.Query(data, "$["); result != nil {
+		t.Errorf("Query(invalid) = %v; want nil", result)
+	}
+}
+
+func TestQueryResultsWithConverters(t *testing.T) {
+	data := mconv.ToMapFromJSON(queryDocument, mconv.JSONUseNumber())
+
+	if age, err := mconv.ToIntE(mconv.GetPointer(data, "/store/users/0/age")); err != nil || age != 34 {
+		t.Errorf("mconv.ToIntE(GetPointer) = %v, %v", age, err)
+	}
+	ages := mconv.Query(data, "$.store.users[*].age")
+	if len(ages) != 3 {
+		t.Fatalf("Query returned %d results", len(ages))
+	}
+	if age, err := mconv.ToIntE(ages[2]); err != nil || age != 41 {
+		t.Errorf("mconv.ToIntE(Query) = %v, %v", age, err)
+	}
+	tags := mconv.Query(data, "$.store.users[2].tags")
+	if got, err := mconv.ToSliceE(tags[0]); err != nil || !reflect.DeepEqual(got, []interface{}{"ops", "admin"}) {
+		t.Errorf("mconv.ToSliceE(Query) = %v, %v", got, err)
+	}
+	// The result slice itself converts element by element.
+	if got, err := mconv.ToIntSliceE(ages); err != nil || !reflect.DeepEqual(got, []int{34, 28, 41}) {
+		t.Errorf("mconv.ToIntSliceE([]*Var) = %v, %v", got, err)
+	}
+}
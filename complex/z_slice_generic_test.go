@@ -1,8 +1,11 @@
 package complex_test
 
 import (
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/graingo/mconv/complex"
 )
@@ -63,3 +66,92 @@ func TestToSliceTE(t *testing.T) {
 		}
 	})
 }
+
+func TestToSliceTEArrays(t *testing.T) {
+	got, err := complex.ToSliceTE[[2]int]([]interface{}{[]string{"1", "2"}, [2]int{3, 4}, []int{5}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][2]int{{1, 2}, {3, 4}, {5, 0}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ToSliceTE[[2]int] = %v; want %v", got, expected)
+	}
+
+	if _, err := complex.ToSliceTE[[2]int]([]interface{}{[]int{1, 2, 3}}); err == nil {
+		t.Error("expected error for source longer than the array")
+	}
+
+	ints, err := complex.ToSliceTE[int]([3]int{1, 2, 3})
+	if err != nil || !reflect.DeepEqual(ints, []int{1, 2, 3}) {
+		t.Errorf("ToSliceTE[int]([3]int) = %v, %v", ints, err)
+	}
+}
+
+func TestToSliceTEEngine(t *testing.T) {
+	t.Run("int32", func(t *testing.T) {
+		got, err := complex.ToSliceTE[int32]([]interface{}{"1", 2.0, json.Number("3")})
+		if err != nil || !reflect.DeepEqual(got, []int32{1, 2, 3}) {
+			t.Errorf("ToSliceTE[int32] = %v, %v", got, err)
+		}
+		if _, err := complex.ToSliceTE[int8]([]int{1, 300}); err == nil {
+			t.Error("expected overflow error")
+		}
+	})
+
+	t.Run("time and duration", func(t *testing.T) {
+		times, err := complex.ToSliceTE[time.Time]([]string{"2024-01-02T03:04:05Z"})
+		if err != nil || len(times) != 1 || times[0].Unix() != 1704164645 {
+			t.Errorf("ToSliceTE[time.Time] = %v, %v", times, err)
+		}
+		durations, err := complex.ToSliceTE[time.Duration]([]string{"1s", "2m"})
+		if err != nil || !reflect.DeepEqual(durations, []time.Duration{time.Second, 2 * time.Minute}) {
+			t.Errorf("ToSliceTE[time.Duration] = %v, %v", durations, err)
+		}
+	})
+
+	t.Run("structs", func(t *testing.T) {
+		type User struct {
+			Name string `json:"name"`
+			Age  int    `json:"age"`
+		}
+		rows := []map[string]interface{}{
+			{"name": "alice", "age": "30"},
+			{"name": "bob", "age": 25},
+		}
+		got, err := complex.ToSliceTE[User](rows)
+		expected := []User{{"alice", 30}, {"bob", 25}}
+		if err != nil || !reflect.DeepEqual(got, expected) {
+			t.Errorf("ToSliceTE[User] = %+v, %v", got, err)
+		}
+		ptrs, err := complex.ToSliceTE[*User](rows)
+		if err != nil || len(ptrs) != 2 || *ptrs[1] != expected[1] {
+			t.Errorf("ToSliceTE[*User] = %v, %v", ptrs, err)
+		}
+	})
+
+	t.Run("nested containers", func(t *testing.T) {
+		got, err := complex.ToSliceTE[[]string]([]interface{}{[]interface{}{"a", 1}, []int{2, 3}})
+		if err != nil || !reflect.DeepEqual(got, [][]string{{"a", "1"}, {"2", "3"}}) {
+			t.Errorf("ToSliceTE[[]string] = %v, %v", got, err)
+		}
+		maps, err := complex.ToSliceTE[map[string]int]([]interface{}{map[string]interface{}{"a": "1"}})
+		if err != nil || !reflect.DeepEqual(maps, []map[string]int{{"a": 1}}) {
+			t.Errorf("ToSliceTE[map[string]int] = %v, %v", maps, err)
+		}
+	})
+
+	t.Run("named types", func(t *testing.T) {
+		type Level int
+		got, err := complex.ToSliceTE[Level]([]string{"1", "2"})
+		if err != nil || !reflect.DeepEqual(got, []Level{1, 2}) {
+			t.Errorf("ToSliceTE[Level] = %v, %v", got, err)
+		}
+	})
+
+	t.Run("element error", func(t *testing.T) {
+		_, err := complex.ToSliceTE[int32]([]string{"1", "x"})
+		if err == nil || !strings.Contains(err.Error(), "element 1") {
+			t.Errorf("expected error naming element 1, got %v", err)
+		}
+	})
+}
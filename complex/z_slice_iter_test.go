@@ -0,0 +1,71 @@
+//go:build go1.23
+
+package complex_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/graingo/mconv"
+	"github.com/graingo/mconv/complex"
+)
+
+func TestToSliceFromSeqEChannel(t *testing.T) {
+	ch := make(chan string, 3)
+	ch <- "a"
+	ch <- "b"
+	ch <- "c"
+	close(ch)
+
+	got, err := mconv.ToSliceFromSeqE(ch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []interface{}{"a", "b", "c"}) {
+		t.Errorf("mconv.ToSliceFromSeqE(chan) = %v", got)
+	}
+
+	// Send-only channels cannot be drained.
+	var send chan<- int = make(chan int)
+	if _, err := mconv.ToSliceFromSeqE(send); err == nil {
+		t.Error("expected error for a send-only channel")
+	}
+	if _, err := mconv.ToSliceFromSeqE([]int{1}); err == nil {
+		t.Error("expected error for a slice")
+	}
+}
+
+func TestToSliceFromSeqEIterSeq(t *testing.T) {
+	got, err := mconv.ToSliceFromSeqE(slices.Values([]int{1, 2, 3}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []interface{}{1, 2, 3}) {
+		t.Errorf("mconv.ToSliceFromSeqE(iter.Seq) = %v", got)
+	}
+
+	ints, err := complex.ToSliceTE[int](mconv.ToSliceFromSeq(slices.Values([]string{"4", "5"})))
+	if err != nil || !reflect.DeepEqual(ints, []int{4, 5}) {
+		t.Errorf("ToSliceTE[int](iter.Seq) = %v, %v", ints, err)
+	}
+}
+
+func TestToSliceEDoesNotDrain(t *testing.T) {
+	// An open channel is wrapped as a single element instead of blocking.
+	ch := make(chan int, 1)
+	ch <- 1
+	got, err := mconv.ToSliceE(ch)
+	if err != nil || len(got) != 1 || got[0] != interface{}(ch) {
+		t.Errorf("mconv.ToSliceE(chan) = %v, %v", got, err)
+	}
+	if len(ch) != 1 {
+		t.Error("ToSliceE received from the channel")
+	}
+
+	called := false
+	seq := func(yield func(int) bool) { called = true }
+	if got, err := mconv.ToSliceE(seq); err != nil || len(got) != 1 || called {
+		t.Errorf("mconv.ToSliceE(iter.Seq) = %v, %v, called = %v", got, err, called)
+	}
+}
@@ -2,7 +2,10 @@ package complex_test
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/graingo/mconv"
 )
@@ -302,3 +305,101 @@ func float64SliceEqual(a, b []float64) bool {
 	}
 	return true
 }
+
+func TestToSliceEArrays(t *testing.T) {
+	arr := [3]int{1, 2, 3}
+	var nilArr *[2]string
+
+	tests := []struct {
+		input    interface{}
+		expected []interface{}
+	}{
+		{arr, []interface{}{1, 2, 3}},
+		{&arr, []interface{}{1, 2, 3}},
+		{[2]string{"a", "b"}, []interface{}{"a", "b"}},
+		{[0]int{}, []interface{}{}},
+		{nilArr, nil},
+	}
+
+	for _, test := range tests {
+		got, err := mconv.ToSliceE(test.input)
+		if err != nil {
+			t.Errorf("mconv.ToSliceE(%v) unexpected error: %v", test.input, err)
+			continue
+		}
+		if (got == nil) != (test.expected == nil) || len(got) != len(test.expected) {
+			t.Errorf("mconv.ToSliceE(%v) = %v; want %v", test.input, got, test.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.expected[i] {
+				t.Errorf("mconv.ToSliceE(%v)[%d] = %v; want %v", test.input, i, got[i], test.expected[i])
+			}
+		}
+	}
+}
+
+func TestTypedSlices(t *testing.T) {
+	src := []interface{}{"1", 2, 3.0}
+
+	if got := mconv.ToInt64Slice(src); !reflect.DeepEqual(got, []int64{1, 2, 3}) {
+		t.Errorf("mconv.ToInt64Slice = %v", got)
+	}
+	if got := mconv.ToInt32Slice(src); !reflect.DeepEqual(got, []int32{1, 2, 3}) {
+		t.Errorf("mconv.ToInt32Slice = %v", got)
+	}
+	if got := mconv.ToUintSlice(src); !reflect.DeepEqual(got, []uint{1, 2, 3}) {
+		t.Errorf("mconv.ToUintSlice = %v", got)
+	}
+	if got := mconv.ToUint64Slice([3]string{"1", "2", "3"}); !reflect.DeepEqual(got, []uint64{1, 2, 3}) {
+		t.Errorf("mconv.ToUint64Slice = %v", got)
+	}
+	if got := mconv.ToFloat32Slice([]string{"1.5", "2"}); !reflect.DeepEqual(got, []float32{1.5, 2}) {
+		t.Errorf("mconv.ToFloat32Slice = %v", got)
+	}
+	if got := mconv.ToBoolSlice([]interface{}{"yes", 0, true}); !reflect.DeepEqual(got, []bool{true, false, true}) {
+		t.Errorf("mconv.ToBoolSlice = %v", got)
+	}
+	if got := mconv.ToDurationSlice([]string{"1s", "1m"}); !reflect.DeepEqual(got, []time.Duration{time.Second, time.Minute}) {
+		t.Errorf("mconv.ToDurationSlice = %v", got)
+	}
+	times := mconv.ToTimeSlice([]string{"02/01/2024"}, "02/01/2006")
+	if len(times) != 1 || !times[0].Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("mconv.ToTimeSlice = %v", times)
+	}
+
+	same := []int64{4, 5}
+	if got := mconv.ToInt64Slice(same); &got[0] != &same[0] {
+		t.Error("mconv.ToInt64Slice should return []int64 input as-is")
+	}
+	if got := mconv.ToBoolSlice(nil); got != nil {
+		t.Errorf("mconv.ToBoolSlice(nil) = %v; want nil", got)
+	}
+}
+
+func TestTypedSlicesE(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{"int64", func() error { _, err := mconv.ToInt64SliceE([]string{"1", "x"}); return err }},
+		{"int32", func() error { _, err := mconv.ToInt32SliceE([]int64{1, 1 << 40}); return err }},
+		{"uint", func() error { _, err := mconv.ToUintSliceE([]int{1, -1}); return err }},
+		{"uint64", func() error { _, err := mconv.ToUint64SliceE([]string{"1", "-2"}); return err }},
+		{"float32", func() error { _, err := mconv.ToFloat32SliceE([]string{"1", "abc"}); return err }},
+		{"bool", func() error { _, err := mconv.ToBoolSliceE([]string{"true", "maybe"}); return err }},
+		{"time", func() error { _, err := mconv.ToTimeSliceE([]string{"2024-01-02", "never"}); return err }},
+		{"duration", func() error { _, err := mconv.ToDurationSliceE([]string{"1s", "soon"}); return err }},
+	}
+
+	for _, test := range tests {
+		err := test.fn()
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), "element 1") {
+			t.Errorf("%s: error %q does not report the element index", test.name, err)
+		}
+	}
+}
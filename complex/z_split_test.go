@@ -0,0 +1,145 @@
+package complex_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/graingo/mconv/complex"
+)
+
+func TestSplitString(t *testing.T) {
+	opts := complex.DefaultSplitOptions()
+	space := complex.SplitOptions{Separators: " ", TrimSpace: true, Quotes: true, Escapes: true}
+	multi := complex.SplitOptions{Separators: ",; ", TrimSpace: true}
+	omit := complex.DefaultSplitOptions()
+	omit.OmitEmpty = true
+
+	tests := []struct {
+		input    string
+		opts     complex.SplitOptions
+		expected []string
+		isErr    bool
+	}{
+		{"a,b,c", opts, []string{"a", "b", "c"}, false},
+		{" a , b ,c ", opts, []string{"a", "b", "c"}, false},
+		{"", opts, []string{}, false},
+		{"a,,b", opts, []string{"a", "", "b"}, false},
+		{"a,,b,", omit, []string{"a", "b"}, false},
+		{`"a,b", c`, opts, []string{"a,b", "c"}, false},
+		{`' padded ',x`, opts, []string{" padded ", "x"}, false},
+		{`a\,b,c`, opts, []string{"a,b", "c"}, false},
+		{`"a\"b"`, opts, []string{`a"b`}, false},
+		{`["x", 1, true]`, opts, []string{"x", "1", "true"}, false},
+		{"[]", opts, []string{}, false},
+		{"[a,b]", opts, []string{"[a", "b]"}, false},
+		{"  a \t b\n c  ", space, []string{"a", "b", "c"}, false},
+		{`a "b c" d`, space, []string{"a", "b c", "d"}, false},
+		{"a; b, c d", multi, []string{"a", "b", "c", "d"}, false},
+		{"a , b", multi, []string{"a", "b"}, false},
+		{`"unterminated`, opts, nil, true},
+		{`trailing\`, opts, nil, true},
+	}
+
+	for _, test := range tests {
+		got, err := complex.SplitString(test.input, test.opts)
+		if test.isErr {
+			if err == nil {
+				t.Errorf("SplitString(%q) expected error", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SplitString(%q) unexpected error: %v", test.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("SplitString(%q) = %q; want %q", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestStringSplittingMode(t *testing.T) {
+	// Plain converters never split.
+	if got := complex.ToStringSlice("a,b"); !reflect.DeepEqual(got, []string{"a,b"}) {
+		t.Errorf("ToStringSlice = %q", got)
+	}
+	if got := complex.ToSlice("a,b"); !reflect.DeepEqual(got, []interface{}{"a,b"}) {
+		t.Errorf("ToSlice = %q", got)
+	}
+
+	opts := complex.DefaultSplitOptions()
+	if got := complex.ToStringSlice(complex.ToSliceWithSplit("a, b, c", opts)); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("ToStringSlice(ToSliceWithSplit) = %q", got)
+	}
+	if got, err := complex.ToIntSliceE(complex.ToSliceWithSplit("1,2,3", opts)); err != nil || !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("ToIntSliceE(ToSliceWithSplit) = %v, %v", got, err)
+	}
+	if got, err := complex.ToIntSliceE(complex.ToSliceWithSplit("[1,2,3]", opts)); err != nil || !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("ToIntSliceE(JSON) = %v, %v", got, err)
+	}
+	if got, err := complex.ToSliceWithSplitE("[1.5]", opts); err != nil || !reflect.DeepEqual(got, []interface{}{json.Number("1.5")}) {
+		t.Errorf("ToSliceWithSplitE(JSON) = %#v, %v", got, err)
+	}
+	if got, err := complex.ToSliceTE[float64](complex.ToSliceWithSplit("1.5, 2.5", opts)); err != nil || !reflect.DeepEqual(got, []float64{1.5, 2.5}) {
+		t.Errorf("ToSliceTE[float64] = %v, %v", got, err)
+	}
+	if _, err := complex.ToIntSliceE(complex.ToSliceWithSplit("1,x", opts)); err == nil {
+		t.Error("expected error for invalid element")
+	}
+	if _, err := complex.ToSliceWithSplitE(`"open`, opts); err == nil {
+		t.Error("expected error for an unterminated quote")
+	}
+
+	type Config struct {
+		Tags  []string `mconv:"tags"`
+		Ports []int    `mconv:"ports"`
+		Name  string   `mconv:"name"`
+	}
+	source := map[string]interface{}{"tags": "a,b,c", "ports": "80, 443", "name": "x,y"}
+	var cfg Config
+	if err := complex.ToStructE(source, &cfg, complex.SplitHook(opts)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Config{Tags: []string{"a", "b", "c"}, Ports: []int{80, 443}, Name: "x,y"}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("ToStructE = %+v; want %+v", cfg, expected)
+	}
+
+	// Without the hook the string is a single element.
+	var plain Config
+	if err := complex.ToStructE(map[string]interface{}{"tags": "a,b"}, &plain); err != nil || !reflect.DeepEqual(plain.Tags, []string{"a,b"}) {
+		t.Errorf("ToStructE without SplitHook = %+v, %v", plain, err)
+	}
+}
+
+func TestSplitPerCall(t *testing.T) {
+	opts := complex.DefaultSplitOptions()
+
+	got, err := complex.ToSliceWithSplitE("a, b", opts)
+	if err != nil || !reflect.DeepEqual(got, []interface{}{"a", "b"}) {
+		t.Errorf("ToSliceWithSplitE = %v, %v", got, err)
+	}
+	if ports, err := complex.ToSliceTE[int](complex.ToSliceWithSplit("80, 443", opts)); err != nil || !reflect.DeepEqual(ports, []int{80, 443}) {
+		t.Errorf("ToSliceTE[int](ToSliceWithSplit) = %v, %v", ports, err)
+	}
+	if got := complex.ToSliceWithSplit([]int{1}, opts); !reflect.DeepEqual(got, []interface{}{1}) {
+		t.Errorf("ToSliceWithSplit(slice) = %v", got)
+	}
+
+	type Config struct {
+		Tags  []string `mconv:"tags"`
+		Ports [2]int   `mconv:"ports"`
+		Name  string   `mconv:"name"`
+	}
+	source := map[string]interface{}{"tags": "a;b", "ports": "80 443", "name": "x,y"}
+	var cfg Config
+	err = complex.ToStructE(source, &cfg, complex.SplitHook(complex.SplitOptions{Separators: "; ", TrimSpace: true}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Config{Tags: []string{"a", "b"}, Ports: [2]int{80, 443}, Name: "x,y"}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("ToStructE(SplitHook) = %+v; want %+v", cfg, expected)
+	}
+}
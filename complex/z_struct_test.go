@@ -1,10 +1,15 @@
 package complex_test
 
 import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/graingo/mconv/basic"
 	"github.com/graingo/mconv/complex"
 )
 
@@ -433,3 +438,241 @@ func TestStruct(t *testing.T) {
 		}
 	})
 }
+
+func TestStructBigNumbers(t *testing.T) {
+	type Transfer struct {
+		Amount *big.Int   `mconv:"amount"`
+		Rate   *big.Float `mconv:"rate"`
+		Share  *big.Rat   `mconv:"share"`
+	}
+
+	source := map[string]interface{}{
+		"amount": "115792089237316195423570985008687907853269984665640564039457584007913129639935",
+		"rate":   "0.5",
+		"share":  "1/3",
+	}
+	var target Transfer
+	if err := complex.ToStructE(source, &target); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Amount.String() != source["amount"] {
+		t.Errorf("Amount: expected %v, got %v", source["amount"], target.Amount)
+	}
+	if f, _ := target.Rate.Float64(); f != 0.5 {
+		t.Errorf("Rate: expected 0.5, got %v", target.Rate)
+	}
+	if target.Share.RatString() != "1/3" {
+		t.Errorf("Share: expected 1/3, got %v", target.Share)
+	}
+}
+
+func TestStructDecimal(t *testing.T) {
+	type Invoice struct {
+		Total    basic.Decimal  `mconv:"total"`
+		Discount *basic.Decimal `mconv:"discount"`
+		Tax      basic.Decimal  `mconv:"tax"`
+	}
+
+	source := map[string]interface{}{
+		"total":    "19.99",
+		"discount": 0.1,
+		"tax":      json.Number("1.60"),
+	}
+	var target Invoice
+	if err := complex.ToStructE(source, &target); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Total.String() != "19.99" {
+		t.Errorf("Total: expected 19.99, got %v", target.Total)
+	}
+	if target.Discount == nil || target.Discount.String() != "0.1" {
+		t.Errorf("Discount: expected 0.1, got %v", target.Discount)
+	}
+	if target.Tax.String() != "1.60" {
+		t.Errorf("Tax: expected 1.60, got %v", target.Tax)
+	}
+}
+
+func TestStructJSONNumber(t *testing.T) {
+	type Event struct {
+		ID       int64     `json:"id"`
+		Count    uint32    `json:"count"`
+		Ratio    float64   `json:"ratio"`
+		Enabled  bool      `json:"enabled"`
+		At       time.Time `json:"at"`
+		Duration time.Duration
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(`{"id":9007199254740993,"count":3,"ratio":0.25,"enabled":1,"at":1700000000,"Duration":1000}`))
+	decoder.UseNumber()
+	var source map[string]interface{}
+	if err := decoder.Decode(&source); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var target Event
+	if err := complex.ToStructE(source, &target); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Event{
+		ID:       9007199254740993,
+		Count:    3,
+		Ratio:    0.25,
+		Enabled:  true,
+		At:       time.Unix(1700000000, 0),
+		Duration: time.Microsecond,
+	}
+	if !reflect.DeepEqual(target, expected) {
+		t.Errorf("expected %+v, got %+v", expected, target)
+	}
+}
+
+func TestStructSQLNull(t *testing.T) {
+	type Row struct {
+		Name      sql.NullString  `mconv:"name"`
+		Age       sql.NullInt64   `mconv:"age"`
+		Score     sql.NullFloat64 `mconv:"score"`
+		CreatedAt sql.NullTime    `mconv:"created_at"`
+		Deleted   sql.NullBool    `mconv:"deleted"`
+		Nickname  *sql.NullString `mconv:"nickname"`
+		Email     string          `mconv:"email"`
+		Phone     *string         `mconv:"phone"`
+	}
+
+	source := map[string]interface{}{
+		"name":       "alice",
+		"age":        "30",
+		"score":      nil,
+		"created_at": "2024-01-02T03:04:05Z",
+		"deleted":    sql.NullBool{Bool: true, Valid: true},
+		"nickname":   "al",
+		"email":      sql.NullString{String: "a@example.com", Valid: true},
+		"phone":      sql.NullString{},
+	}
+	var target Row
+	if err := complex.ToStructE(source, &target); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if target.Name != (sql.NullString{String: "alice", Valid: true}) {
+		t.Errorf("Name: got %+v", target.Name)
+	}
+	if target.Age != (sql.NullInt64{Int64: 30, Valid: true}) {
+		t.Errorf("Age: got %+v", target.Age)
+	}
+	if target.Score.Valid {
+		t.Errorf("Score: expected invalid, got %+v", target.Score)
+	}
+	if !target.CreatedAt.Valid || target.CreatedAt.Time.Unix() != 1704164645 {
+		t.Errorf("CreatedAt: got %+v", target.CreatedAt)
+	}
+	if !target.Deleted.Valid || !target.Deleted.Bool {
+		t.Errorf("Deleted: got %+v", target.Deleted)
+	}
+	if target.Nickname == nil || *target.Nickname != (sql.NullString{String: "al", Valid: true}) {
+		t.Errorf("Nickname: got %+v", target.Nickname)
+	}
+	if target.Email != "a@example.com" {
+		t.Errorf("Email: got %q", target.Email)
+	}
+	if target.Phone != nil {
+		t.Errorf("Phone: expected nil for invalid NullString, got %q", *target.Phone)
+	}
+
+	// Values that cannot be converted into the Null type's value are reported.
+	if err := complex.ToStructE(map[string]interface{}{"age": "old"}, &target); err == nil {
+		t.Error("expected error for invalid NullInt64 value")
+	}
+}
+
+func TestStructArrays(t *testing.T) {
+	type Target struct {
+		RGB    [3]uint8   `mconv:"rgb"`
+		Pair   [2]string  `mconv:"pair"`
+		Matrix [2][2]int  `mconv:"matrix"`
+		Ptr    *[2]string `mconv:"ptr"`
+	}
+
+	source := map[string]interface{}{
+		"rgb":    []interface{}{255, "128", 0.0},
+		"pair":   [1]string{"left"},
+		"matrix": [][]int{{1, 2}, {3, 4}},
+		"ptr":    []string{"x", "y"},
+	}
+	var target Target
+	if err := complex.ToStructE(source, &target); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if target.RGB != [3]uint8{255, 128, 0} {
+		t.Errorf("RGB: got %v", target.RGB)
+	}
+	if target.Pair != [2]string{"left", ""} {
+		t.Errorf("Pair: got %v", target.Pair)
+	}
+	if target.Matrix != [2][2]int{{1, 2}, {3, 4}} {
+		t.Errorf("Matrix: got %v", target.Matrix)
+	}
+	if target.Ptr == nil || *target.Ptr != [2]string{"x", "y"} {
+		t.Errorf("Ptr: got %v", target.Ptr)
+	}
+
+	err := complex.ToStructE(map[string]interface{}{"rgb": []int{1, 2, 3, 4}}, &target)
+	if err == nil {
+		t.Error("expected error for source longer than the array field")
+	}
+}
+
+func TestStructTagPaths(t *testing.T) {
+	type DTO struct {
+		Author    string   `mconv:"meta.author.name"`
+		FirstID   int      `mconv:"items[0].id"`
+		SecondTag string   `mconv:"items[1].tags[0]"`
+		Matrix    int      `mconv:"grid[1][0]"`
+		Dotted    string   `mconv:"a\\.b"`
+		Missing   string   `mconv:"meta.none.name"`
+		OutOfBand int      `mconv:"items[5].id"`
+		JSONDots  string   `json:"x.y"`
+		Tags      []string `mconv:"items[1].tags"`
+	}
+
+	source := map[string]interface{}{
+		"meta": map[string]interface{}{
+			"Author": map[string]string{"name": "alice"},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"id": "7"},
+			struct {
+				Tags []string `json:"tags"`
+			}{Tags: []string{"go", "json"}},
+		},
+		"grid": [][]int{{1}, {2}},
+		"a.b":  "literal",
+		"x":    map[string]interface{}{"y": "nested"},
+	}
+
+	var dto DTO
+	if err := complex.ToStructE(source, &dto); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := DTO{
+		Author:    "alice",
+		FirstID:   7,
+		SecondTag: "go",
+		Matrix:    2,
+		Dotted:    "literal",
+		Tags:      []string{"go", "json"},
+	}
+	if !reflect.DeepEqual(dto, expected) {
+		t.Errorf("ToStructE = %+v; want %+v", dto, expected)
+	}
+
+	// A literal top-level key that equals the tag still wins.
+	var flat DTO
+	if err := complex.ToStructE(map[string]interface{}{"meta.author.name": "bob"}, &flat); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flat.Author != "bob" {
+		t.Errorf("Author = %q; want bob", flat.Author)
+	}
+}
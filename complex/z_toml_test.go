@@ -0,0 +1,238 @@
+package complex_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graingo/mconv"
+	"github.com/graingo/mconv/internal"
+)
+
+func TestToMapFromTOMLE(t *testing.T) {
+	input := `# This is a TOML document
+title = "TOML Example"
+"quoted key" = 'C:\Users\nodejs'
+site."google.com" = true
+
+[owner]
+name = "Tom Preston-Werner"
+dob = 1979-05-27T07:32:00-08:00
+
+[database]
+enabled = true
+ports = [ 8000, 8001, 8002 ]
+data = [ ["delta", "phi"], [3.14] ]
+temp_targets = { cpu = 79.5, case = 72.0 }
+
+[servers]
+
+  [servers.alpha]
+  ip = "10.0.0.1"
+  role = "frontend"
+
+[[products]]
+name = "Hammer"
+sku = 738594937
+
+[[products]]  # empty table within the array
+
+[[products]]
+name = "Nail"
+color.name = "gray"
+`
+	result, err := mconv.ToMapFromTOMLE(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"title":      "TOML Example",
+		"quoted key": `C:\Users\nodejs`,
+		"site":       map[string]interface{}{"google.com": true},
+		"owner": map[string]interface{}{
+			"name": "Tom Preston-Werner",
+			"dob":  time.Date(1979, 5, 27, 7, 32, 0, 0, time.FixedZone("", -8*3600)),
+		},
+		"database": map[string]interface{}{
+			"enabled":      true,
+			"ports":        []interface{}{int64(8000), int64(8001), int64(8002)},
+			"data":         []interface{}{[]interface{}{"delta", "phi"}, []interface{}{3.14}},
+			"temp_targets": map[string]interface{}{"cpu": 79.5, "case": 72.0},
+		},
+		"servers": map[string]interface{}{
+			"alpha": map[string]interface{}{"ip": "10.0.0.1", "role": "frontend"},
+		},
+		"products": []interface{}{
+			map[string]interface{}{"name": "Hammer", "sku": int64(738594937)},
+			map[string]interface{}{},
+			map[string]interface{}{"name": "Nail", "color": map[string]interface{}{"name": "gray"}},
+		},
+	}
+	dob := result["owner"].(map[string]interface{})["dob"].(time.Time)
+	if !dob.Equal(expected["owner"].(map[string]interface{})["dob"].(time.Time)) {
+		t.Errorf("dob = %v", dob)
+	}
+	result["owner"].(map[string]interface{})["dob"] = expected["owner"].(map[string]interface{})["dob"]
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ToMapFromTOMLE() = %#v; want %#v", result, expected)
+	}
+
+	if result, err := mconv.ToMapFromTOMLE(""); result != nil || err != nil {
+		t.Errorf("ToMapFromTOMLE(\"\") = %v, %v; want nil, nil", result, err)
+	}
+}
+
+func TestTOMLValues(t *testing.T) {
+	input := `int1 = +99
+int2 = -17
+int3 = 1_000
+hex = 0xDEAD_beef
+oct = 0o755
+bin = 0b1101
+float1 = 6.626e-34
+float2 = -0.01
+float3 = 224_617.445_991
+float4 = 5e+22
+inf = -inf
+odt = 1979-05-27 07:32:00.999Z
+ldt = 1979-05-27T07:32:00
+ld = 1979-05-27
+lt = 07:32:00.5
+str1 = "tab\t quote\" unicode\u00E9 \U0001F600"
+str2 = """
+Roses are red
+Violets are "blue"."""
+str3 = """\
+       The quick brown \
+       fox."""
+str4 = '''
+raw \n text
+'''
+str5 = """quotes"" """
+arr = [
+  1,
+  "mixed", # comment
+  { a = 1 },
+]
+`
+	result, err := mconv.ToMapFromTOMLE(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"int1": int64(99), "int2": int64(-17), "int3": int64(1000),
+		"hex": int64(0xdeadbeef), "oct": int64(0o755), "bin": int64(13),
+		"float1": 6.626e-34, "float2": -0.01, "float3": 224617.445991, "float4": 5e22,
+		"inf":  math.Inf(-1),
+		"odt":  time.Date(1979, 5, 27, 7, 32, 0, 999000000, time.UTC),
+		"ldt":  time.Date(1979, 5, 27, 7, 32, 0, 0, time.Local),
+		"ld":   time.Date(1979, 5, 27, 0, 0, 0, 0, time.Local),
+		"lt":   time.Date(0, 1, 1, 7, 32, 0, 500000000, time.Local),
+		"str1": "tab\t quote\" unicodeé 😀",
+		"str2": "Roses are red\nViolets are \"blue\".",
+		"str3": "The quick brown fox.",
+		"str4": "raw \\n text\n",
+		"str5": `quotes"" `,
+		"arr":  []interface{}{int64(1), "mixed", map[string]interface{}{"a": int64(1)}},
+	}
+	for key, want := range expected {
+		got := result[key]
+		if wantTime, ok := want.(time.Time); ok {
+			if gotTime, ok := got.(time.Time); !ok || !gotTime.Equal(wantTime) {
+				t.Errorf("%s = %v; want %v", key, got, want)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %#v; want %#v", key, got, want)
+		}
+	}
+
+	nan := mconv.ToMapFromTOML("n = nan")["n"]
+	if f, ok := nan.(float64); !ok || !math.IsNaN(f) {
+		t.Errorf("expected NaN, got %#v", nan)
+	}
+}
+
+func TestTOMLToStruct(t *testing.T) {
+	type Server struct {
+		Host    string   `toml:"host"`
+		Port    int      `toml:"port"`
+		Aliases []string `toml:"aliases"`
+	}
+	type Config struct {
+		Name    string    `toml:"name"`
+		Created time.Time `toml:"created"`
+		Server  Server    `toml:"server"`
+		Workers []struct {
+			ID int `toml:"id"`
+		} `toml:"workers"`
+	}
+	input := `name = "svc"
+created = 2024-01-02T03:04:05Z
+
+[server]
+host = "localhost"
+port = 8080
+aliases = ["a", "b"]
+
+[[workers]]
+id = 1
+[[workers]]
+id = 2
+`
+	m, err := mconv.ToMapFromTOMLE(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var config Config
+	if err := mconv.ToStructE(m, &config); err != nil {
+		t.Fatalf("ToStructE() error = %v", err)
+	}
+	if config.Name != "svc" || !config.Created.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) ||
+		!reflect.DeepEqual(config.Server, Server{Host: "localhost", Port: 8080, Aliases: []string{"a", "b"}}) ||
+		len(config.Workers) != 2 || config.Workers[1].ID != 2 {
+		t.Errorf("ToStructE() = %+v", config)
+	}
+}
+
+func TestTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		line, column int
+	}{
+		{"duplicate key", "a = 1\na = 2\n", 2, 1},
+		{"duplicate table", "[a]\nb = 1\n[a]\n", 3, 1},
+		{"table over dotted keys", "[fruit]\napple.color = 'red'\n[fruit.apple]\n", 3, 1},
+		{"dotted keys into table", "[a.b]\nz = 1\n[a]\nb.y = 2\n", 4, 1},
+		{"extend inline table", "a = {b = 1}\n[a.c]\n", 2, 1},
+		{"array of tables over array", "a = []\n[[a]]\n", 2, 1},
+		{"missing value", "a =\n", 1, 4},
+		{"two values on a line", "a = 1 b = 2\n", 1, 7},
+		{"leading zero", "a = 012\n", 1, 5},
+		{"bad underscore", "a = 1__0\n", 1, 5},
+		{"invalid date", "a = 1979-13-27\n", 1, 5},
+		{"unterminated string", "a = \"text\n", 1, 5},
+		{"invalid escape", `a = "\q"`, 1, 6},
+		{"trailing comma in inline table", "a = {b = 1,}\n", 1, 12},
+		{"unterminated array", "a = [1, 2\n", 1, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := mconv.ToMapFromTOMLE(tt.input)
+			if !errors.Is(err, internal.ErrInvalidTOMLFormat) {
+				t.Fatalf("error = %v; want ErrInvalidTOMLFormat", err)
+			}
+			var tomlErr *mconv.TOMLError
+			if !errors.As(err, &tomlErr) {
+				t.Fatalf("error = %v; want *TOMLError", err)
+			}
+			if tomlErr.Line != tt.line || tomlErr.Column != tt.column {
+				t.Errorf("position = %d:%d; want %d:%d (%v)", tomlErr.Line, tomlErr.Column, tt.line, tt.column, err)
+			}
+		})
+	}
+}
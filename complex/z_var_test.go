@@ -0,0 +1,230 @@
+package complex_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graingo/mconv"
+	"github.com/graingo/mconv/complex"
+)
+
+func TestVar(t *testing.T) {
+	v := complex.NewVar("42")
+	if v.Int() != 42 || v.Int32() != 42 || v.Int64() != 42 || v.Uint() != 42 || v.Uint64() != 42 {
+		t.Errorf("integer accessors for %q failed", v.Interface())
+	}
+	if v.Float32() != 42 || v.Float64() != 42 || v.String() != "42" {
+		t.Errorf("float and string accessors for %q failed", v.Interface())
+	}
+	if _, err := v.BoolE(); err == nil {
+		t.Error("expected error converting \"42\" to bool")
+	}
+	if _, err := complex.NewVar("x").IntE(); err == nil {
+		t.Error("expected error converting \"x\" to int")
+	}
+
+	if got := complex.NewVar("02/01/2024").Time("02/01/2006"); !got.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time with layout = %v", got)
+	}
+	if got := complex.NewVar("1m30s").Duration(); got != 90*time.Second {
+		t.Errorf("Duration = %v", got)
+	}
+	if got := complex.NewVar([]int{1, 2}).Slice(); !reflect.DeepEqual(got, []interface{}{1, 2}) {
+		t.Errorf("Slice = %v", got)
+	}
+	if got := complex.NewVar([]int{1, 2}).Strings(); !reflect.DeepEqual(got, []string{"1", "2"}) {
+		t.Errorf("Strings = %v", got)
+	}
+	if got := complex.NewVar(map[string]int{"a": 1}).Map(); !reflect.DeepEqual(got, map[string]interface{}{"a": 1}) {
+		t.Errorf("Map = %v", got)
+	}
+
+	type User struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	var user User
+	if err := complex.NewVar(map[string]interface{}{"name": "alice", "age": "30"}).Struct(&user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user != (User{"alice", 30}) {
+		t.Errorf("Struct = %+v", user)
+	}
+}
+
+func TestVarNilAndEmpty(t *testing.T) {
+	var nilPtr *int
+	tests := []struct {
+		value interface{}
+		isNil bool
+		empty bool
+	}{
+		{nil, true, true},
+		{nilPtr, true, true},
+		{[]int(nil), true, true},
+		{"", false, true},
+		{0, false, true},
+		{[]int{}, false, true},
+		{map[string]int{}, false, true},
+		{time.Time{}, false, true},
+		{"a", false, false},
+		{1, false, false},
+		{[]int{0}, false, false},
+	}
+	for _, test := range tests {
+		v := complex.NewVar(test.value)
+		if v.IsNil() != test.isNil {
+			t.Errorf("NewVar(%#v).IsNil() = %v; want %v", test.value, v.IsNil(), test.isNil)
+		}
+		if v.IsEmpty() != test.empty {
+			t.Errorf("NewVar(%#v).IsEmpty() = %v; want %v", test.value, v.IsEmpty(), test.empty)
+		}
+	}
+
+	var nilVar *complex.Var
+	if !nilVar.IsNil() || !nilVar.IsEmpty() || nilVar.String() != "" || nilVar.Int() != 0 {
+		t.Error("nil *Var should behave like a Var wrapping nil")
+	}
+}
+
+func TestVarMemoization(t *testing.T) {
+	v := complex.NewVar([]string{"a"})
+	first := v.Slice()
+	second := v.Slice()
+	if &first[0] != &second[0] {
+		t.Error("Slice should return the memoized result")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := complex.NewVar("7").Int(); got != 7 {
+				t.Errorf("Int = %d", got)
+			}
+			_ = v.String()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestVarMarshalling(t *testing.T) {
+	type Payload struct {
+		ID    *complex.Var `json:"id"`
+		Extra *complex.Var `json:"extra"`
+	}
+
+	var p Payload
+	if err := json.Unmarshal([]byte(`{"id": 12345678901234567890, "extra": {"k": [1, "x"]}}`), &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.ID.String(); got != "12345678901234567890" {
+		t.Errorf("ID.String() = %q", got)
+	}
+	if got := p.ID.Uint64(); got != 12345678901234567890 {
+		t.Errorf("ID.Uint64() = %d", got)
+	}
+
+	out, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != `{"id":12345678901234567890,"extra":{"k":[1,"x"]}}` {
+		t.Errorf("json.Marshal = %s", out)
+	}
+
+	v := complex.NewVar(1)
+	_ = v.Int()
+	if err := v.UnmarshalText([]byte("2")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Int() != 2 {
+		t.Errorf("Int after UnmarshalText = %d; memoized value was not reset", v.Int())
+	}
+	text, err := complex.NewVar(1.5).MarshalText()
+	if err != nil || string(text) != "1.5" {
+		t.Errorf("MarshalText = %s, %v", text, err)
+	}
+}
+
+func TestVarWithConverters(t *testing.T) {
+	v := complex.NewVar(json.Number("42"))
+	if got, err := mconv.ToIntE(v); err != nil || got != 42 {
+		t.Errorf("mconv.ToIntE(Var) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToUint8E(v); err != nil || got != 42 {
+		t.Errorf("mconv.ToUint8E(Var) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToFloat64E(v); err != nil || got != 42 {
+		t.Errorf("mconv.ToFloat64E(Var) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToBigIntE(v); err != nil || got.Int64() != 42 {
+		t.Errorf("mconv.ToBigIntE(Var) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToDecimalE(v); err != nil || got.String() != "42" {
+		t.Errorf("mconv.ToDecimalE(Var) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToBoolStrictE(complex.NewVar("true")); err != nil || !got {
+		t.Errorf("mconv.ToBoolStrictE(Var) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToDurationE(complex.NewVar("1s")); err != nil || got != time.Second {
+		t.Errorf("mconv.ToDurationE(Var) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToTimeE(complex.NewVar("2024-01-02T03:04:05Z")); err != nil || got.Year() != 2024 {
+		t.Errorf("mconv.ToTimeE(Var) = %v, %v", got, err)
+	}
+
+	list := complex.NewVar([]interface{}{"1", 2})
+	if got, err := mconv.ToSliceE(list); err != nil || !reflect.DeepEqual(got, []interface{}{"1", 2}) {
+		t.Errorf("mconv.ToSliceE(Var) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToIntSliceE(list); err != nil || !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("mconv.ToIntSliceE(Var) = %v, %v", got, err)
+	}
+	if got, err := mconv.ToStringSliceE(list); err != nil || !reflect.DeepEqual(got, []string{"1", "2"}) {
+		t.Errorf("mconv.ToStringSliceE(Var) = %v, %v", got, err)
+	}
+	if got, err := complex.ToSliceTE[int64](list); err != nil || !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("ToSliceTE[int64](Var) = %v, %v", got, err)
+	}
+
+	object := complex.NewVar(map[string]interface{}{"port": "80"})
+	if got, err := mconv.ToIntMapE(object); err != nil || !reflect.DeepEqual(got, map[string]int{"port": 80}) {
+		t.Errorf("mconv.ToIntMapE(Var) = %v, %v", got, err)
+	}
+	var cfg struct {
+		Port int          `mconv:"port"`
+		Raw  *complex.Var `mconv:"raw"`
+	}
+	if err := mconv.ToStructE(object, &cfg); err != nil || cfg.Port != 80 {
+		t.Errorf("mconv.ToStructE(Var) = %+v, %v", cfg, err)
+	}
+	nested := map[string]interface{}{"port": complex.NewVar(8080), "raw": v}
+	if err := mconv.ToStructE(nested, &cfg); err != nil || cfg.Port != 8080 || cfg.Raw != v {
+		t.Errorf("mconv.ToStructE(fields of Var) = %+v, %v", cfg, err)
+	}
+
+	var nilVar *complex.Var
+	if got, err := mconv.ToIntE(nilVar); err != nil || got != 0 {
+		t.Errorf("mconv.ToIntE(nil Var) = %v, %v", got, err)
+	}
+}
+
+func TestVarCopy(t *testing.T) {
+	v := complex.NewVar(1)
+	copied := *v
+	if copied.Int() != 1 {
+		t.Errorf("copy Int = %d", copied.Int())
+	}
+	var zero complex.Var
+	if zero.Int() != 0 || zero.Interface() != nil {
+		t.Errorf("zero Var = %v", zero.Interface())
+	}
+	if err := zero.UnmarshalText([]byte("3")); err != nil || zero.Int() != 3 {
+		t.Errorf("zero Var after UnmarshalText = %d, %v", zero.Int(), err)
+	}
+}
@@ -0,0 +1,326 @@
+package complex_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graingo/mconv"
+	"github.com/graingo/mconv/internal"
+)
+
+func TestFromYAMLE(t *testing.T) {
+	type Database struct {
+		Host    string        `yaml:"host"`
+		Port    int           `yaml:"port"`
+		Timeout time.Duration `yaml:"timeout"`
+	}
+	type Config struct {
+		Name     string            `yaml:"name"`
+		Debug    bool              `yaml:"debug"`
+		Ratio    float64           `yaml:"ratio"`
+		Tags     []string          `yaml:"tags"`
+		Database Database          `yaml:"database"`
+		Labels   map[string]string `yaml:"labels"`
+	}
+
+	input := `# service configuration
+name: api
+debug: true
+ratio: 0.75
+tags:
+- web
+- "internal"
+database:
+  host: localhost   # local only
+  port: 5432
+  timeout: 5s
+labels: {team: core, tier: '1'}
+`
+	var config Config
+	if err := mconv.FromYAMLE(input, &config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Config{
+		Name:     "api",
+		Debug:    true,
+		Ratio:    0.75,
+		Tags:     []string{"web", "internal"},
+		Database: Database{Host: "localhost", Port: 5432, Timeout: 5 * time.Second},
+		Labels:   map[string]string{"team": "core", "tier": "1"},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("FromYAMLE() = %+v; want %+v", config, expected)
+	}
+
+	var ports []int
+	if err := mconv.FromYAMLE("- 80\n- '443'\n", &ports); err != nil || !reflect.DeepEqual(ports, []int{80, 443}) {
+		t.Errorf("FromYAMLE() = %v, %v; want [80 443]", ports, err)
+	}
+	if err := mconv.FromYAMLE("a: 1", config); err == nil {
+		t.Error("expected error for non-pointer target")
+	}
+}
+
+func TestToMapFromYAMLE(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]interface{}
+	}{
+		{"empty", "", nil},
+		{"comments only", "# nothing\n", nil},
+		{
+			"nested",
+			"a:\n  b:\n    c: 1\n  d: [x, y]\n",
+			map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": 1}, "d": []interface{}{"x", "y"}}},
+		},
+		{
+			"sequence of mappings",
+			"items:\n  - name: a\n    size: 1\n  -\n    name: b\n  - - nested\n",
+			map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"name": "a", "size": 1},
+				map[string]interface{}{"name": "b"},
+				[]interface{}{"nested"},
+			}},
+		},
+		{
+			"flow collections",
+			"a: {b: [1, 2, {c: d}], 'e': \"f\", g: }\nh: [k: v, ]\ni: [\n  1,  # one\n  2\n]\n",
+			map[string]interface{}{
+				"a": map[string]interface{}{"b": []interface{}{1, 2, map[string]interface{}{"c": "d"}}, "e": "f", "g": nil},
+				"h": []interface{}{map[string]interface{}{"k": "v"}},
+				"i": []interface{}{1, 2},
+			},
+		},
+		{
+			"keys",
+			"1: one\ntrue: yes\n\"quoted key\": 2\nurl: http://example.com:8080/path\n",
+			map[string]interface{}{"1": "one", "true": "yes", "quoted key": 2, "url": "http://example.com:8080/path"},
+		},
+		{
+			"explicit document",
+			"%YAML 1.2\n---\na: 1\n...\n",
+			map[string]interface{}{"a": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := mconv.ToMapFromYAMLE(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ToMapFromYAMLE() = %#v; want %#v", result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := mconv.ToMapFromYAMLE("- a\n- b\n"); err == nil {
+		t.Error("expected error for a sequence document")
+	}
+	if _, err := mconv.ToMapFromYAMLE("a: 1\n---\nb: 2\n"); err == nil {
+		t.Error("expected error for multiple documents")
+	}
+}
+
+func TestYAMLScalars(t *testing.T) {
+	input := `null1: ~
+null2: null
+null3:
+bool1: true
+bool2: False
+int1: 42
+int2: -17
+int3: 0o17
+int4: 0x1F
+int5: +3
+big: 18446744073709551615
+float1: 1.5
+float2: -.5e3
+float3: 1.
+inf: -.inf
+str1: 1.2.3
+str2: yes
+str3: "true"
+str4: 'it''s'
+str5: "tab\there \u00e9 \x41"
+str6: 0x
+str7: !!str 12
+tagged1: !!float 3
+tagged2: !!int "7"
+`
+	result, err := mconv.ToMapFromYAMLE(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"null1": nil, "null2": nil, "null3": nil,
+		"bool1": true, "bool2": false,
+		"int1": 42, "int2": -17, "int3": 15, "int4": 31, "int5": 3,
+		"big":    uint64(math.MaxUint64),
+		"float1": 1.5, "float2": -500.0, "float3": 1.0,
+		"inf":  math.Inf(-1),
+		"str1": "1.2.3", "str2": "yes", "str3": "true", "str4": "it's",
+		"str5": "tab\there é A", "str6": "0x", "str7": "12",
+		"tagged1": 3.0, "tagged2": 7,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ToMapFromYAMLE() = %#v; want %#v", result, expected)
+	}
+
+	nan := mconv.ToMapFromYAML("n: .NaN")["n"]
+	if f, ok := nan.(float64); !ok || !math.IsNaN(f) {
+		t.Errorf("expected NaN, got %#v", nan)
+	}
+	if _, err := mconv.ToMapFromYAMLE("n: !!int abc"); err == nil {
+		t.Error("expected error for !!int abc")
+	}
+}
+
+func TestYAMLMultiLineStrings(t *testing.T) {
+	input := `literal: |
+  line 1
+    indented
+  line 3
+
+folded: >
+  folded
+  text
+
+  new paragraph
+    kept
+  end
+strip: |-
+  no newline
+keep: >+
+  kept
+
+indent: |2
+    two extra
+plain: first
+  second
+
+  third
+quoted: "one
+  two\
+  three"
+single: 'a
+  b'
+last: end`
+	result, err := mconv.ToMapFromYAMLE(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"literal": "line 1\n  indented\nline 3\n",
+		"folded":  "folded text\nnew paragraph\n  kept\nend\n",
+		"strip":   "no newline",
+		"keep":    "kept\n\n",
+		"indent":  "  two extra\n",
+		"plain":   "first second\nthird",
+		"quoted":  "one twothree",
+		"single":  "a b",
+		"last":    "end",
+	}
+	for key, want := range expected {
+		if got := result[key]; got != want {
+			t.Errorf("%s = %q; want %q", key, got, want)
+		}
+	}
+}
+
+func TestYAMLAnchors(t *testing.T) {
+	input := `defaults: &defaults
+  adapter: postgres
+  host: localhost
+  port: 5432
+hosts: &hosts [a, b]
+development:
+  <<: *defaults
+  database: dev
+test:
+  <<: [*defaults, {pool: 5}]
+  host: test.local
+copy: *hosts
+`
+	result, err := mconv.ToMapFromYAMLE(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedDev := map[string]interface{}{"adapter": "postgres", "host": "localhost", "port": 5432, "database": "dev"}
+	if !reflect.DeepEqual(result["development"], expectedDev) {
+		t.Errorf("development = %#v; want %#v", result["development"], expectedDev)
+	}
+	expectedTest := map[string]interface{}{"adapter": "postgres", "host": "test.local", "port": 5432, "pool": 5}
+	if !reflect.DeepEqual(result["test"], expectedTest) {
+		t.Errorf("test = %#v; want %#v", result["test"], expectedTest)
+	}
+	if !reflect.DeepEqual(result["copy"], []interface{}{"a", "b"}) {
+		t.Errorf("copy = %#v; want [a b]", result["copy"])
+	}
+	if _, err := mconv.ToMapFromYAMLE("a: *missing"); err == nil {
+		t.Error("expected error for unknown anchor")
+	}
+}
+
+func TestToDocumentsFromYAMLE(t *testing.T) {
+	input := `a: 1
+---
+- x
+--- plain
+---
+...
+--- |
+  text
+`
+	docs, err := mconv.ToDocumentsFromYAMLE(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"a": 1},
+		[]interface{}{"x"},
+		"plain",
+		nil,
+		"text\n",
+	}
+	if !reflect.DeepEqual(docs, expected) {
+		t.Errorf("ToDocumentsFromYAMLE() = %#v; want %#v", docs, expected)
+	}
+}
+
+func TestYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		line, column int
+	}{
+		{"duplicate key", "a: 1\nb: 2\na: 3\n", 3, 2},
+		{"bad indentation", "a:\n  b:\n    c: 1\n   d: 2\n", 4, 4},
+		{"bad sequence indentation", "- 'a'\n  - b\n", 2, 3},
+		{"mapping in plain scalar", "a:\n  b: 1\n   c: 2\n", 3, 5},
+		{"mapping in scalar", "a: b: c\n", 1, 5},
+		{"unterminated quote", "a: \"text\n", 1, 4},
+		{"unterminated flow", "a: [1, 2\n", 1, 4},
+		{"invalid escape", `a: "\q"`, 1, 5},
+		{"complex key", "? a\n: b\n", 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := mconv.ToMapFromYAMLE(tt.input)
+			if !errors.Is(err, internal.ErrInvalidYAMLFormat) {
+				t.Fatalf("error = %v; want ErrInvalidYAMLFormat", err)
+			}
+			var yamlErr *mconv.YAMLError
+			if !errors.As(err, &yamlErr) {
+				t.Fatalf("error = %v; want *YAMLError", err)
+			}
+			if yamlErr.Line != tt.line || yamlErr.Column != tt.column {
+				t.Errorf("position = %d:%d; want %d:%d (%v)", yamlErr.Line, yamlErr.Column, tt.line, tt.column, err)
+			}
+		})
+	}
+}
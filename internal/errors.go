@@ -14,6 +14,9 @@ var (
 	ErrInvalidFormat     = errors.New("invalid format")
 	ErrInvalidTimeFormat = errors.New("invalid time format")
 	ErrInvalidJSONFormat = errors.New("invalid JSON format")
+	ErrInvalidYAMLFormat = errors.New("invalid YAML format")
+	ErrInvalidTOMLFormat = errors.New("invalid TOML format")
+	ErrInvalidINIFormat  = errors.New("invalid INI format")
 )
 
 // ConversionError represents a conversion error.
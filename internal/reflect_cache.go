@@ -236,6 +236,19 @@ type FieldDecoder struct {
 	Index []int
 	// Name is the key name in the source map to look for.
 	Name string
+	// Path is the parsed form of a dotted tag such as "meta.items[0].id".
+	// It is nil when Name is a plain key.
+	Path []PathSegment
+}
+
+// PathSegment is a single step of a FieldDecoder path.
+type PathSegment struct {
+	// Key is the map key to look up when IsIndex is false.
+	Key string
+	// Index is the slice index to look up when IsIndex is true.
+	Index int
+	// IsIndex reports whether the segment is a slice index.
+	IsIndex bool
 }
 
 // Decoder holds the complete decoding plan for a struct type.
@@ -0,0 +1,25 @@
+package internal
+
+import "reflect"
+
+// Wrapper is implemented by values that wrap another value, such as complex.Var.
+type Wrapper interface {
+	Interface() interface{}
+}
+
+// Unwrap returns the value wrapped by value when it implements Wrapper, and reports
+// whether it did. reflect.Value is not treated as a wrapper, and a nil pointer
+// wrapper unwraps to nil.
+func Unwrap(value interface{}) (interface{}, bool) {
+	w, ok := value.(Wrapper)
+	if !ok {
+		return value, false
+	}
+	if _, ok := value.(reflect.Value); ok {
+		return value, false
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, true
+	}
+	return w.Interface(), true
+}
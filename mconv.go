@@ -9,6 +9,94 @@ import (
 // HookFunc is an alias of complex.HookFunc.
 type HookFunc = complex.HookFunc
 
+// Decimal is an alias of basic.Decimal.
+type Decimal = basic.Decimal
+
+// SplitOptions is an alias of complex.SplitOptions.
+type SplitOptions = complex.SplitOptions
+
+// Var is an alias of complex.Var.
+type Var = complex.Var
+
+// JSONOption is an alias of complex.JSONOption.
+type JSONOption = complex.JSONOption
+
+// JSONError is an alias of complex.JSONError.
+type JSONError = complex.JSONError
+
+// YAMLError is an alias of complex.YAMLError.
+type YAMLError = complex.YAMLError
+
+// TOMLError is an alias of complex.TOMLError.
+type TOMLError = complex.TOMLError
+
+// INIError is an alias of complex.INIError.
+type INIError = complex.INIError
+
+// ComplexEncoding is an alias of complex.ComplexEncoding.
+type ComplexEncoding = complex.ComplexEncoding
+
+// Complex number encodings for JSONComplexAs.
+const (
+	ComplexAsString = complex.ComplexAsString
+	ComplexAsArray  = complex.ComplexAsArray
+	ComplexAsObject = complex.ComplexAsObject
+)
+
+// NonFiniteEncoding is an alias of complex.NonFiniteEncoding.
+type NonFiniteEncoding = complex.NonFiniteEncoding
+
+// Non-finite float encodings for JSONNonFiniteAs.
+const (
+	NonFiniteAsNull   = complex.NonFiniteAsNull
+	NonFiniteAsString = complex.NonFiniteAsString
+	NonFiniteAsError  = complex.NonFiniteAsError
+)
+
+// PatchOperation is an alias of complex.PatchOperation.
+type PatchOperation = complex.PatchOperation
+
+// MergeOptions is an alias of complex.MergeOptions.
+type MergeOptions = complex.MergeOptions
+
+// FlattenOptions is an alias of complex.FlattenOptions.
+type FlattenOptions = complex.FlattenOptions
+
+// IndexStyle is an alias of complex.IndexStyle.
+type IndexStyle = complex.IndexStyle
+
+// Index styles for FlattenOptions.
+const (
+	IndexSep      = complex.IndexSep
+	IndexBrackets = complex.IndexBrackets
+	IndexNone     = complex.IndexNone
+)
+
+// SliceStrategy is an alias of complex.SliceStrategy.
+type SliceStrategy = complex.SliceStrategy
+
+// Slice strategies for MergeOptions.
+const (
+	SliceReplace      = complex.SliceReplace
+	SliceAppend       = complex.SliceAppend
+	SliceAppendUnique = complex.SliceAppendUnique
+	SliceMergeByKey   = complex.SliceMergeByKey
+)
+
+// RoundingMode is an alias of basic.RoundingMode.
+type RoundingMode = basic.RoundingMode
+
+// Rounding modes for Decimal.Round and Decimal.DivRound.
+const (
+	RoundHalfUp   = basic.RoundHalfUp
+	RoundHalfEven = basic.RoundHalfEven
+	RoundHalfDown = basic.RoundHalfDown
+	RoundUp       = basic.RoundUp
+	RoundDown     = basic.RoundDown
+	RoundCeiling  = basic.RoundCeiling
+	RoundFloor    = basic.RoundFloor
+)
+
 var (
 	// ToString convert any type to string.
 	ToString = basic.ToString
@@ -70,6 +158,16 @@ var (
 	ToBool = basic.ToBool
 	// ToBoolE convert any type to bool with error.
 	ToBoolE = basic.ToBoolE
+	// ToBoolStrict convert bool or "true"/"false" string to bool.
+	ToBoolStrict = basic.ToBoolStrict
+	// ToBoolStrictE convert bool or "true"/"false" string to bool with error.
+	ToBoolStrictE = basic.ToBoolStrictE
+	// RegisterBoolWords register words recognized by ToBool.
+	RegisterBoolWords = basic.RegisterBoolWords
+	// UnregisterBoolWords remove words recognized by ToBool.
+	UnregisterBoolWords = basic.UnregisterBoolWords
+	// ResetBoolWords restore the built-in words recognized by ToBool.
+	ResetBoolWords = basic.ResetBoolWords
 
 	// ToComplex128 convert any type to complex128.
 	ToComplex128 = basic.ToComplex128
@@ -87,6 +185,30 @@ var (
 	ToDuration = basic.ToDuration
 	// ToDurationE convert any type to time.Duration with error.
 	ToDurationE = basic.ToDurationE
+
+	// ToBigInt convert any type to *big.Int.
+	ToBigInt = basic.ToBigInt
+	// ToBigIntE convert any type to *big.Int with error.
+	ToBigIntE = basic.ToBigIntE
+	// ToBigFloat convert any type to *big.Float.
+	ToBigFloat = basic.ToBigFloat
+	// ToBigFloatE convert any type to *big.Float with error.
+	ToBigFloatE = basic.ToBigFloatE
+	// ToBigRat convert any type to *big.Rat.
+	ToBigRat = basic.ToBigRat
+	// ToBigRatE convert any type to *big.Rat with error.
+	ToBigRatE = basic.ToBigRatE
+
+	// ToDecimal convert any type to Decimal.
+	ToDecimal = basic.ToDecimal
+	// ToDecimalE convert any type to Decimal with error.
+	ToDecimalE = basic.ToDecimalE
+	// NewDecimal create a Decimal from an unscaled value and a scale.
+	NewDecimal = basic.NewDecimal
+	// NewDecimalFromBigInt create a Decimal from an unscaled *big.Int and a scale.
+	NewDecimalFromBigInt = basic.NewDecimalFromBigInt
+	// ParseDecimal parse a decimal string.
+	ParseDecimal = basic.ParseDecimal
 )
 
 var (
@@ -94,6 +216,10 @@ var (
 	ToSlice = complex.ToSlice
 	// ToSliceE convert any type to slice with error.
 	ToSliceE = complex.ToSliceE
+	// ToSliceFromSeq collect the values of a channel or iter.Seq.
+	ToSliceFromSeq = complex.ToSliceFromSeq
+	// ToSliceFromSeqE collect the values of a channel or iter.Seq with error.
+	ToSliceFromSeqE = complex.ToSliceFromSeqE
 	// ToStringSlice convert any type to slice of string.
 	ToStringSlice = complex.ToStringSlice
 	// ToStringSliceE convert any type to slice of string with error.
@@ -106,6 +232,48 @@ var (
 	ToFloat64Slice = complex.ToFloat64Slice
 	// ToFloat64SliceE convert any type to slice of float64 with error.
 	ToFloat64SliceE = complex.ToFloat64SliceE
+	// ToInt64Slice convert any type to slice of int64.
+	ToInt64Slice = complex.ToInt64Slice
+	// ToInt64SliceE convert any type to slice of int64 with error.
+	ToInt64SliceE = complex.ToInt64SliceE
+	// ToInt32Slice convert any type to slice of int32.
+	ToInt32Slice = complex.ToInt32Slice
+	// ToInt32SliceE convert any type to slice of int32 with error.
+	ToInt32SliceE = complex.ToInt32SliceE
+	// ToUintSlice convert any type to slice of uint.
+	ToUintSlice = complex.ToUintSlice
+	// ToUintSliceE convert any type to slice of uint with error.
+	ToUintSliceE = complex.ToUintSliceE
+	// ToUint64Slice convert any type to slice of uint64.
+	ToUint64Slice = complex.ToUint64Slice
+	// ToUint64SliceE convert any type to slice of uint64 with error.
+	ToUint64SliceE = complex.ToUint64SliceE
+	// ToFloat32Slice convert any type to slice of float32.
+	ToFloat32Slice = complex.ToFloat32Slice
+	// ToFloat32SliceE convert any type to slice of float32 with error.
+	ToFloat32SliceE = complex.ToFloat32SliceE
+	// ToBoolSlice convert any type to slice of bool.
+	ToBoolSlice = complex.ToBoolSlice
+	// ToBoolSliceE convert any type to slice of bool with error.
+	ToBoolSliceE = complex.ToBoolSliceE
+	// ToTimeSlice convert any type to slice of time.Time.
+	ToTimeSlice = complex.ToTimeSlice
+	// ToTimeSliceE convert any type to slice of time.Time with error.
+	ToTimeSliceE = complex.ToTimeSliceE
+	// ToDurationSlice convert any type to slice of time.Duration.
+	ToDurationSlice = complex.ToDurationSlice
+	// ToDurationSliceE convert any type to slice of time.Duration with error.
+	ToDurationSliceE = complex.ToDurationSliceE
+	// DefaultSplitOptions return the default string splitting options.
+	DefaultSplitOptions = complex.DefaultSplitOptions
+	// ToSliceWithSplit convert any type to slice, splitting strings with the given options.
+	ToSliceWithSplit = complex.ToSliceWithSplit
+	// ToSliceWithSplitE convert any type to slice, splitting strings with the given options, with error.
+	ToSliceWithSplitE = complex.ToSliceWithSplitE
+	// SplitHook return a ToStructE hook that splits strings into slice fields.
+	SplitHook = complex.SplitHook
+	// SplitString split a string into slice elements.
+	SplitString = complex.SplitString
 
 	// ToMap convert any type to map.
 	ToMap = complex.ToMap
@@ -123,6 +291,26 @@ var (
 	ToFloat64Map = complex.ToFloat64Map
 	// ToFloat64MapE convert any type to map of float64 with error.
 	ToFloat64MapE = complex.ToFloat64MapE
+	// ToInt64Map convert any type to map of int64.
+	ToInt64Map = complex.ToInt64Map
+	// ToInt64MapE convert any type to map of int64 with error.
+	ToInt64MapE = complex.ToInt64MapE
+	// ToBoolMap convert any type to map of bool.
+	ToBoolMap = complex.ToBoolMap
+	// ToBoolMapE convert any type to map of bool with error.
+	ToBoolMapE = complex.ToBoolMapE
+	// ToDurationMap convert any type to map of time.Duration.
+	ToDurationMap = complex.ToDurationMap
+	// ToDurationMapE convert any type to map of time.Duration with error.
+	ToDurationMapE = complex.ToDurationMapE
+	// ToStringMapStringSlice convert any type to map of string slice.
+	ToStringMapStringSlice = complex.ToStringMapStringSlice
+	// ToStringMapStringSliceE convert any type to map of string slice with error.
+	ToStringMapStringSliceE = complex.ToStringMapStringSliceE
+	// ToNestedMap convert any type to map of map.
+	ToNestedMap = complex.ToNestedMap
+	// ToNestedMapE convert any type to map of map with error.
+	ToNestedMapE = complex.ToNestedMapE
 
 	// ToJSON convert any type to json.
 	ToJSON = complex.ToJSON
@@ -140,6 +328,120 @@ var (
 	ToSliceFromJSON = complex.ToSliceFromJSON
 	// ToSliceFromJSONE convert json to slice with error.
 	ToSliceFromJSONE = complex.ToSliceFromJSONE
+	// FromJSONBytes convert json bytes to any type.
+	FromJSONBytes = complex.FromJSONBytes
+	// FromJSONBytesE convert json bytes to any type with error.
+	FromJSONBytesE = complex.FromJSONBytesE
+	// FromJSONReader convert json from a reader to any type.
+	FromJSONReader = complex.FromJSONReader
+	// FromJSONReaderE convert json from a reader to any type with error.
+	FromJSONReaderE = complex.FromJSONReaderE
+	// ToMapFromJSONBytes convert json bytes to map.
+	ToMapFromJSONBytes = complex.ToMapFromJSONBytes
+	// ToMapFromJSONBytesE convert json bytes to map with error.
+	ToMapFromJSONBytesE = complex.ToMapFromJSONBytesE
+	// ToMapFromJSONReader convert json from a reader to map.
+	ToMapFromJSONReader = complex.ToMapFromJSONReader
+	// ToMapFromJSONReaderE convert json from a reader to map with error.
+	ToMapFromJSONReaderE = complex.ToMapFromJSONReaderE
+	// ToSliceFromJSONBytes convert json bytes to slice.
+	ToSliceFromJSONBytes = complex.ToSliceFromJSONBytes
+	// ToSliceFromJSONBytesE convert json bytes to slice with error.
+	ToSliceFromJSONBytesE = complex.ToSliceFromJSONBytesE
+	// ToSliceFromJSONReader convert json from a reader to slice.
+	ToSliceFromJSONReader = complex.ToSliceFromJSONReader
+	// ToSliceFromJSONReaderE convert json from a reader to slice with error.
+	ToSliceFromJSONReaderE = complex.ToSliceFromJSONReaderE
+	// JSONUseNumber decode json numbers as json.Number.
+	JSONUseNumber = complex.JSONUseNumber
+	// JSONDisallowUnknownFields reject json keys without a matching struct field.
+	JSONDisallowUnknownFields = complex.JSONDisallowUnknownFields
+	// JSONLines treat streamed json input as newline-delimited.
+	JSONLines = complex.JSONLines
+	// JSONRelaxed accept comments, trailing commas and other relaxed json syntax.
+	JSONRelaxed = complex.JSONRelaxed
+	// JSONIndent indent encoded json.
+	JSONIndent = complex.JSONIndent
+	// JSONDisableHTMLEscape write <, > and & unescaped in encoded json.
+	JSONDisableHTMLEscape = complex.JSONDisableHTMLEscape
+	// JSONSortKeys sort struct fields and map keys in encoded json.
+	JSONSortKeys = complex.JSONSortKeys
+	// JSONCanonical encode json in RFC 8785 canonical form.
+	JSONCanonical = complex.JSONCanonical
+	// JSONComplexAs set the json encoding of complex numbers.
+	JSONComplexAs = complex.JSONComplexAs
+	// JSONNonFiniteAs set the json encoding of NaN and infinite floats.
+	JSONNonFiniteAs = complex.JSONNonFiniteAs
+
+	// FromYAML convert yaml to any type.
+	FromYAML = complex.FromYAML
+	// FromYAMLE convert yaml to any type with error.
+	FromYAMLE = complex.FromYAMLE
+	// ToMapFromYAML convert yaml to map.
+	ToMapFromYAML = complex.ToMapFromYAML
+	// ToMapFromYAMLE convert yaml to map with error.
+	ToMapFromYAMLE = complex.ToMapFromYAMLE
+	// ToDocumentsFromYAML convert every document of a yaml stream.
+	ToDocumentsFromYAML = complex.ToDocumentsFromYAML
+	// ToDocumentsFromYAMLE convert every document of a yaml stream with error.
+	ToDocumentsFromYAMLE = complex.ToDocumentsFromYAMLE
+	// ToMapFromTOML convert toml to map.
+	ToMapFromTOML = complex.ToMapFromTOML
+	// ToMapFromTOMLE convert toml to map with error.
+	ToMapFromTOMLE = complex.ToMapFromTOMLE
+	// ToMapFromINI convert ini to map.
+	ToMapFromINI = complex.ToMapFromINI
+	// ToMapFromINIE convert ini to map with error.
+	ToMapFromINIE = complex.ToMapFromINIE
+
+	// Flatten convert nested maps to a single-level map with joined keys.
+	Flatten = complex.Flatten
+	// Unflatten convert a single-level map with joined keys to nested maps.
+	Unflatten = complex.Unflatten
+	// UnflattenE convert a single-level map with joined keys to nested maps with error.
+	UnflattenE = complex.UnflattenE
+	// FlattenWithOptions convert nested maps to a single-level map with options.
+	FlattenWithOptions = complex.FlattenWithOptions
+	// UnflattenWithOptions convert a single-level map to nested maps with options.
+	UnflattenWithOptions = complex.UnflattenWithOptions
+	// UnflattenWithOptionsE convert a single-level map to nested maps with options and error.
+	UnflattenWithOptionsE = complex.UnflattenWithOptionsE
+
+	// NewVar wrap a value of any type.
+	NewVar = complex.NewVar
+	// Get get the value at a path inside nested data.
+	Get = complex.Get
+	// GetE get the value at a path inside nested data with error.
+	GetE = complex.GetE
+	// Set set the value at a path inside nested data.
+	Set = complex.Set
+	// GetPointer get the value referenced by a JSON pointer.
+	GetPointer = complex.GetPointer
+	// GetPointerE get the value referenced by a JSON pointer with error.
+	GetPointerE = complex.GetPointerE
+	// Query get the values matched by a JSONPath expression.
+	Query = complex.Query
+	// QueryE get the values matched by a JSONPath expression with error.
+	QueryE = complex.QueryE
+	// ApplyMergePatch apply a JSON merge patch to a document.
+	ApplyMergePatch = complex.ApplyMergePatch
+	// ApplyMergePatchJSON apply a JSON merge patch to a json document.
+	ApplyMergePatchJSON = complex.ApplyMergePatchJSON
+	// ApplyJSONPatch apply a JSON patch to a document.
+	ApplyJSONPatch = complex.ApplyJSONPatch
+	// ApplyJSONPatchJSON apply a JSON patch to a json document.
+	ApplyJSONPatchJSON = complex.ApplyJSONPatchJSON
+	// Diff create the JSON patch between two documents.
+	Diff = complex.Diff
+	// DiffJSON create the JSON patch between two json documents.
+	DiffJSON = complex.DiffJSON
+
+	// Merge deep-merge maps or structs into a destination.
+	Merge = complex.Merge
+	// MergeE deep-merge maps or structs into a destination with error.
+	MergeE = complex.MergeE
+	// MergeWithOptionsE deep-merge maps or structs into a destination with options and error.
+	MergeWithOptionsE = complex.MergeWithOptionsE
 
 	// ToStruct convert map or struct to struct.
 	ToStruct = complex.ToStruct
@@ -189,3 +491,14 @@ var (
 //   Example:
 //     strMap, err := complex.ToMapTE[string, string](value) // Convert to map[string]string
 //     intMap, err := complex.ToMapTE[string, int](value)    // Convert to map[string]int
+//
+// Generic JSON streaming functions:
+// - complex.StreamJSONE[T any](r io.Reader, fn func(index int, item T, err error) error, opts ...JSONOption) error
+//   Decode a top-level JSON array or NDJSON from a reader one element at a time
+//   Example:
+//     err := complex.StreamJSONE(file, func(i int, user User, err error) error { ... })
+//
+// - complex.StreamJSONSeq[T any](r io.Reader, opts ...JSONOption) iter.Seq2[T, error] (Go 1.23+)
+//   Iterate over the elements decoded by StreamJSONE
+//   Example:
+//     for user, err := range complex.StreamJSONSeq[User](file) { ... }